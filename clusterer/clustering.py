@@ -1,3 +1,5 @@
+import functools
+import gzip
 import logging
 
 import numpy as np
@@ -12,6 +14,116 @@ import json
 
 logger = logging.getLogger(__name__)
 
+
+def message_key(record):
+    return (str(record.get("chatId")), str(record.get("messageId")))
+
+
+# Batches contain edits and deletions of already received
+# messages as well, so they are applied to entities instead of being appended
+def apply_changes(entities, payload):
+    index = {message_key(entity): i for i, entity in enumerate(entities)}
+    removed = set()
+    changed = {}
+
+    for record in payload:
+        key = message_key(record)
+        change_type = record.get("changeType", "CHANGE_TYPE_NEW")
+
+        if change_type == "CHANGE_TYPE_DELETED" or not record.get("text"):
+            if key in index:
+                removed.add(key)
+            changed.pop(key, None)
+            continue
+
+        if key in index and change_type != "CHANGE_TYPE_EDITED":
+            continue
+
+        if key in index:
+            entities[index[key]] = record
+        else:
+            index[key] = len(entities)
+            entities.append(record)
+        removed.discard(key)
+        changed[key] = record
+
+    entities[:] = [entity for entity in entities if message_key(entity) not in removed]
+    return list(changed.values())
+
+
+# Raises on formats that can't be decoded here (jsonl.zst, pb, parquet), so
+# that stream notification stays pending instead of being acknowledged
+def load_batch(data, format):
+    if format == "json":
+        return json.loads(data)
+    if format == "jsonl.gz":
+        data = gzip.decompress(data)
+    elif format != "jsonl":
+        raise ValueError(f"Unsupported batch format: {format}")
+    return [json.loads(line) for line in data.splitlines() if line]
+
+
+def stopped(queue):
+    try:
+        if queue.get(block=False) == None:
+            logger.debug("Got stop signal")
+            return True
+    except:
+        pass
+    return False
+
+
+def read_pubsub(r, queue):
+    pubsub = r.pubsub()
+    pubsub.subscribe(settings.redis.channel)
+
+    for msg in pubsub.listen():
+        if stopped(queue):
+            return
+        if msg is None:
+            continue
+
+        logger.debug("Received message: %s", msg)
+        if msg["type"] != "message":
+            continue
+
+        yield msg["data"], lambda: None
+
+
+# Notifications are acknowledged only after they're processed,
+# so the ones left pending by a crash are read again (from id "0") on restart
+def read_stream(r, queue):
+    stream = settings.redis.channel
+    group = settings.redis.group
+    consumer = settings.redis.consumer
+
+    try:
+        r.xgroup_create(stream, group, id="0", mkstream=True)
+    except redis.ResponseError as e:
+        if not str(e).startswith("BUSYGROUP"):
+            raise
+
+    last_id = "0"
+    while not stopped(queue):
+        resp = r.xreadgroup(group, consumer, {stream: last_id}, count=10, block=5000)
+        entries = resp[0][1] if resp else []
+        if not entries:
+            last_id = ">"
+            continue
+        # Entries failed again stay pending until next restart
+        if last_id != ">":
+            last_id = entries[-1][0]
+
+        for entry_id, fields in entries:
+            logger.debug("Received entry: %s", entry_id)
+            ack = functools.partial(r.xack, stream, group, entry_id)
+            # Pending entries trimmed from the stream have no fields
+            if not fields or b"notification" not in fields:
+                ack()
+                continue
+            yield fields[b"notification"], ack
+
+
 def clustering(queue):
     logger.setLevel(logging.DEBUG)
 
@@ -49,64 +161,71 @@ def clustering(queue):
         entities = []
 
 
-    pubsub = r.pubsub()
-    pubsub.subscribe("inbrief")
-
     dim = model.encode("Hello world!", task="separation").shape[0]
     logger.debug("Embedding dimension: %s", dim)
 
     umap = UMAP(n_neighbors=15, n_components=2, metric='cosine')
 
-    for msg in pubsub.listen():
-        try:
-            try:
-                if queue.get(block=False) == None:
-                    logger.debug("Got stop signal")
-                    break
-            except:
-                pass
-            if msg is None:
-                continue
-
-            logger.debug("Received message: %s", msg)
-            if msg["type"] != "message":
-                continue
-
-            filename = f"{msg['data'].decode('utf-8')}.json"
-            resp = s3.get_object("inbrief", filename)
-            payload = resp.json()
-
-            new_texts = list(map(lambda x: x['text'], payload))
+    def process(data):
+        notification = json.loads(data)
+        if notification.get("version") != 1:
+            logger.warning("Unsupported notification version: %s", notification.get("version"))
+            return
+        if notification["sink"] != "s3":
+            return
+        resp = s3.get_object(notification["bucket"], notification["key"])
+        payload = load_batch(resp.data, notification["format"])
+
+        changed = apply_changes(entities, payload)
+        if changed:
+            new_texts = list(map(lambda x: x['text'], changed))
             logger.debug("Encoding texts")
             new_embeddings = model.encode(new_texts, task="separation").tolist()
 
-            for i, entity in enumerate(payload):
+            for i, entity in enumerate(changed):
                 entity['embedding'] = new_embeddings[i]
 
-            entities.extend(payload)
-
-            texts = list((map(lambda x: x['text'], entities)))
-            embeddings = np.array(list(map(lambda x: x['embedding'], entities)))
+        if not entities:
+            return
 
-            try:
-                logger.debug("Reducing embeddings")
-                reduced_embeddings = umap.fit_transform(embeddings)
-                logger.debug("Fitting bertopic")
-                bertopic.fit_transform(texts, embeddings=embeddings)
+        texts = list((map(lambda x: x['text'], entities)))
+        embeddings = np.array(list(map(lambda x: x['embedding'], entities)))
 
-                logger.debug("Writing topics")
-                bertopic.visualize_topics().write_html("static/topics.html")
-                logger.debug("Writing documents")
-                bertopic.visualize_documents(texts, reduced_embeddings=reduced_embeddings).write_html("static/documents.html")
-            except Exception as e:
-                logger.warning(f"Got an error while clustering, will try later: {e}", exc_info=True)
-                continue
+        try:
+            logger.debug("Reducing embeddings")
+            reduced_embeddings = umap.fit_transform(embeddings)
+            logger.debug("Fitting bertopic")
+            bertopic.fit_transform(texts, embeddings=embeddings)
+
+            logger.debug("Writing topics")
+            bertopic.visualize_topics().write_html("static/topics.html")
+            logger.debug("Writing documents")
+            bertopic.visualize_documents(texts, reduced_embeddings=reduced_embeddings).write_html("static/documents.html")
+        except Exception as e:
+            logger.warning(f"Got an error while clustering, will try later: {e}", exc_info=True)
+            return
+
+        logger.debug("Number of texts: %s", len(texts))
+        dumped_entities = json.dumps(entities)
+        r.set("clustering:entities", dumped_entities)
+        dumped_topics = json.dumps(bertopic.topics_)
+        r.set("clustering:topics", dumped_topics)
+
+    if settings.redis.mode == "stream":
+        if not settings.redis.group:
+            logger.error("Stream notifications require APP_REDIS_GROUP")
+            return
+        notifications = read_stream(r, queue)
+    elif settings.redis.mode == "pubsub":
+        notifications = read_pubsub(r, queue)
+    else:
+        logger.error("Unknown notification mode: %s", settings.redis.mode)
+        return
 
-            logger.debug("Number of texts: %s", len(texts))
-            dumped_entities = json.dumps(entities)
-            r.set("clustering:entities", dumped_entities)
-            dumped_topics = json.dumps(bertopic.topics_)
-            r.set("clustering:topics", dumped_topics)
+    for data, ack in notifications:
+        try:
+            process(data)
+            ack()
         # NOTE(nrydanov): Change my mind
         except Exception as e:
             logger.error(f"Error in clustering service: {e}", exc_info=True)
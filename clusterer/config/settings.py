@@ -8,6 +8,10 @@ class ModelSettings(BaseSettings):
 class RedisSettings(BaseSettings):
     host: str = "127.0.0.1"
     port: int = 6379
+    channel: str = "inbrief"
+    mode: str = "pubsub"
+    group: str = ""
+    consumer: str = "clusterer"
 
 class MinioSettings(BaseSettings):
     endpoint: str = "127.0.0.1:9000"
@@ -8,7 +8,6 @@ import (
 	"syscall"
 
 	"github.com/nrydanov/inbrief/internal"
-	"github.com/nrydanov/inbrief/pkg/channels"
 	"github.com/nrydanov/inbrief/pkg/log"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,7 +16,13 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/nrydanov/inbrief/config"
 	"github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/chats"
+	"github.com/nrydanov/inbrief/internal/dedup"
+	"github.com/nrydanov/inbrief/internal/jobs"
 	"github.com/nrydanov/inbrief/internal/server"
+	"github.com/nrydanov/inbrief/internal/sink"
+	"github.com/nrydanov/inbrief/internal/subscription"
+	"github.com/nrydanov/inbrief/internal/textproc"
 	"github.com/nrydanov/inbrief/internal/tl"
 
 	"github.com/redis/go-redis/v9"
@@ -41,10 +46,35 @@ func main() {
 		defaultlog.Fatalf("Failed to init logger: %v", err)
 	}
 
+	text, err := textproc.FromConfig(cfg.Text)
+	if err != nil {
+		zap.L().Fatal("Failed to build text pipeline", zap.Error(err))
+	}
+
+	var nearDup *dedup.NearDuplicates
+	if cfg.Streaming.NearDup.On {
+		mode, err := dedup.ParseMode(cfg.Streaming.NearDup.Mode)
+		if err != nil {
+			zap.L().Fatal("Failed to configure near-duplicate detection", zap.Error(err))
+		}
+		nearDup = dedup.NewNearDuplicates(
+			cfg.Streaming.NearDup.Window,
+			cfg.Streaming.NearDup.Threshold,
+			mode,
+		)
+	}
+
 	tlClient := tl.InitClient(ctx, *cfg)
 
 	var rdb *redis.Client
 	var s3Client *s3.S3
+	var subs *subscription.Store
+	var cursors *subscription.Cursors
+	var seen *dedup.Seen
+	var media *internal.MediaUploader
+	var jobManager *jobs.Manager
+	var output sink.Sink
+	var notifier internal.Notifier
 	if cfg.Streaming.On {
 		{
 			rdb = redis.NewClient(&redis.Options{
@@ -58,9 +88,34 @@ func main() {
 			} else {
 				zap.L().Info("Initialized Redis client successfully")
 			}
+
+			subs, err = subscription.NewStore(ctx, rdb, cfg.Redis.SubscriptionsKey)
+			if err != nil {
+				zap.L().Fatal("Failed to load subscriptions", zap.Error(err))
+			}
+
+			cursors = subscription.NewCursors(rdb, cfg.Redis.CursorsKey)
+
+			notifier, err = internal.NewNotifier(
+				ctx,
+				rdb,
+				cfg.Redis.NotifyMode,
+				cfg.Redis.Channel,
+				cfg.Redis.StreamMaxLen,
+				cfg.Redis.StreamGroup,
+			)
+			if err != nil {
+				zap.L().Fatal("Failed to initialize notifier", zap.Error(err))
+			}
+
+			if cfg.Streaming.Dedup.On {
+				seen = dedup.NewSeen(rdb, cfg.Redis.SeenPrefix, cfg.Streaming.Dedup.TTL)
+			}
 		}
 
-		{
+		// S3 is only needed if it's one of the sinks or media
+		// are written there
+		if sink.Enabled(cfg.Sink, sink.KindS3) || cfg.Streaming.Media.On {
 			session := session.Must(session.NewSession())
 			s3Client = s3.New(
 				session,
@@ -78,16 +133,50 @@ func main() {
 			} else {
 				zap.L().Info("Initialized S3 client successfully")
 			}
+
+			s3Client.Config.S3ForcePathStyle = aws.Bool(true)
+
+			if cfg.S3.CreateBucket {
+				if err = sink.EnsureBucket(s3Client, cfg.S3.Bucket); err != nil {
+					zap.L().Fatal("Failed to create bucket", zap.Error(err))
+				}
+			}
+		}
+
+		output, err = sink.FromConfig(ctx, cfg.Sink, s3Client, cfg.S3)
+		if err != nil {
+			zap.L().Fatal("Failed to initialize sinks", zap.Error(err))
 		}
+		zap.L().Info("Initialized sinks", zap.Strings("kinds", cfg.Sink.Kinds))
 
-		s3Client.Config.S3ForcePathStyle = aws.Bool(true)
+		// Fetch job pages are written to the same sinks
+		jobManager = jobs.NewManager(ctx, jobs.NewStore(rdb, cfg.Redis.JobsKey))
+
+		if cfg.Streaming.Media.On {
+			media = internal.NewMediaUploader(
+				tlClient,
+				s3Client,
+				cfg.S3.Bucket,
+				cfg.S3.Prefix,
+				cfg.Streaming.Media.MaxSize,
+				cfg.Streaming.Media.MimeTypes,
+				cfg.Streaming.Media.QueueSize,
+			)
+			zap.L().Info("Media download is enabled")
+		}
 	}
 
 	state := internal.AppState{
-		TlClient:    tlClient,
-		RedisClient: rdb,
-		Listener:    tlClient.GetListener(),
-		S3Client:    s3Client,
+		TlClient:      tlClient,
+		RedisClient:   rdb,
+		Listener:      tlClient.GetListener(),
+		S3Client:      s3Client,
+		Subscriptions: subs,
+		Media:         media,
+		Text:          text,
+		Chats:         chats.NewCache(cfg.Telegram.ChatCacheTTL, tl.ResolveChat(tlClient)),
+		Jobs:          jobManager,
+		Sink:          output,
 		Channels: &internal.ChannelState{
 			ServerCh:   make(chan *fetcher.Message),
 			ListenerCh: make(chan *fetcher.Message),
@@ -100,33 +189,75 @@ func main() {
 
 	wg := sync.WaitGroup{}
 
+	var recoveryCursors *subscription.Cursors
+	if cfg.Streaming.Recovery.On {
+		recoveryCursors = cursors
+	}
+
 	eventHandler := tl.NewEventHandler(
 		state.Channels.ListenerCh,
 		state.TlClient,
+		state.Subscriptions,
+		state.Media,
+		state.Text,
+		state.Chats,
+		recoveryCursors,
+		cfg.Streaming.Recovery.MaxMessages,
+		cfg.Streaming.Recovery.MaxPending,
 		cfg.Streaming.BatchSize,
 	)
 
-	writer := internal.NewWriter(
-		channels.FanIn(ctx, state.Channels.ServerCh, state.Channels.ListenerCh),
-		state.S3Client,
-		state.RedisClient,
-		cfg.Redis.Channel,
-	)
+	// Fetched and streamed messages are written by separate
+	// writers, so that every batch belongs to a single source partition
+	newWriter := func(
+		ch <-chan *fetcher.Message,
+		source string,
+		cursors *subscription.Cursors,
+	) *internal.Writer {
+		return internal.NewWriter(
+			ch,
+			source,
+			output,
+			notifier,
+			cursors,
+			seen,
+			nearDup,
+		)
+	}
+	writers := []*internal.Writer{
+		// Only streamed messages move cursors, otherwise
+		// fetched history could move cursor past stream messages that are
+		// not flushed yet
+		newWriter(state.Channels.ServerCh, sink.SourceFetch, nil),
+		newWriter(state.Channels.ListenerCh, sink.SourceStream, cursors),
+	}
 
 	// NOTE(nrydanov): App workers
 	{
-		wg.Add(3)
+		wg.Add(2)
 		go func() {
 			defer wg.Done()
 			eventHandler.Handle(ctx, state.Listener, state.RedisClient)
 			zap.L().Debug("Event handler is stopped")
 		}()
 
-		go func() {
-			defer wg.Done()
-			writer.Listen(ctx, cfg.Streaming.BatchSize)
-			zap.L().Debug("Notifier is stopped")
-		}()
+		if state.Media != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				state.Media.Run(ctx, cfg.Streaming.Media.Workers)
+				zap.L().Debug("Media uploader is stopped")
+			}()
+		}
+
+		for _, writer := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				writer.Listen(ctx, cfg.Streaming.BatchSize)
+				zap.L().Debug("Notifier is stopped")
+			}()
+		}
 
 		go func() {
 			defer wg.Done()
@@ -136,6 +267,16 @@ func main() {
 		wg.Wait()
 	}
 
+	if state.Jobs != nil {
+		state.Jobs.Wait()
+	}
+
+	if output != nil {
+		if err := output.Close(); err != nil {
+			zap.L().Error("Failed to close sinks", zap.Error(err))
+		}
+	}
+
 	if err != nil {
 		zap.L().Fatal("Failed to close client", zap.Error(err))
 	} else {
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestNormalizePrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"  ", ""},
+		{"inbrief", "inbrief/"},
+		{"inbrief/", "inbrief/"},
+		{"/inbrief//", "inbrief/"},
+		{" raw/messages ", "raw/messages/"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePrefix(tt.prefix); got != tt.want {
+			t.Errorf("NormalizePrefix(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -22,6 +22,299 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MediaType int32
+
+const (
+	MediaType_MEDIA_TYPE_UNSPECIFIED MediaType = 0
+	MediaType_MEDIA_TYPE_TEXT        MediaType = 1
+	MediaType_MEDIA_TYPE_PHOTO       MediaType = 2
+	MediaType_MEDIA_TYPE_VIDEO       MediaType = 3
+	MediaType_MEDIA_TYPE_DOCUMENT    MediaType = 4
+	MediaType_MEDIA_TYPE_ANIMATION   MediaType = 5
+	MediaType_MEDIA_TYPE_AUDIO       MediaType = 6
+)
+
+// Enum value maps for MediaType.
+var (
+	MediaType_name = map[int32]string{
+		0: "MEDIA_TYPE_UNSPECIFIED",
+		1: "MEDIA_TYPE_TEXT",
+		2: "MEDIA_TYPE_PHOTO",
+		3: "MEDIA_TYPE_VIDEO",
+		4: "MEDIA_TYPE_DOCUMENT",
+		5: "MEDIA_TYPE_ANIMATION",
+		6: "MEDIA_TYPE_AUDIO",
+	}
+	MediaType_value = map[string]int32{
+		"MEDIA_TYPE_UNSPECIFIED": 0,
+		"MEDIA_TYPE_TEXT":        1,
+		"MEDIA_TYPE_PHOTO":       2,
+		"MEDIA_TYPE_VIDEO":       3,
+		"MEDIA_TYPE_DOCUMENT":    4,
+		"MEDIA_TYPE_ANIMATION":   5,
+		"MEDIA_TYPE_AUDIO":       6,
+	}
+)
+
+func (x MediaType) Enum() *MediaType {
+	p := new(MediaType)
+	*p = x
+	return p
+}
+
+func (x MediaType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MediaType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fetcher_fetch_proto_enumTypes[0].Descriptor()
+}
+
+func (MediaType) Type() protoreflect.EnumType {
+	return &file_proto_fetcher_fetch_proto_enumTypes[0]
+}
+
+func (x MediaType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MediaType.Descriptor instead.
+func (MediaType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{0}
+}
+
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNSPECIFIED ChangeType = 0
+	ChangeType_CHANGE_TYPE_NEW         ChangeType = 1
+	ChangeType_CHANGE_TYPE_EDITED      ChangeType = 2
+	ChangeType_CHANGE_TYPE_DELETED     ChangeType = 3
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_TYPE_UNSPECIFIED",
+		1: "CHANGE_TYPE_NEW",
+		2: "CHANGE_TYPE_EDITED",
+		3: "CHANGE_TYPE_DELETED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_TYPE_UNSPECIFIED": 0,
+		"CHANGE_TYPE_NEW":         1,
+		"CHANGE_TYPE_EDITED":      2,
+		"CHANGE_TYPE_DELETED":     3,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fetcher_fetch_proto_enumTypes[1].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_proto_fetcher_fetch_proto_enumTypes[1]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{1}
+}
+
+type EntityType int32
+
+const (
+	EntityType_ENTITY_TYPE_UNSPECIFIED  EntityType = 0
+	EntityType_ENTITY_TYPE_URL          EntityType = 1
+	EntityType_ENTITY_TYPE_TEXT_URL     EntityType = 2
+	EntityType_ENTITY_TYPE_MENTION      EntityType = 3
+	EntityType_ENTITY_TYPE_MENTION_NAME EntityType = 4
+	EntityType_ENTITY_TYPE_HASHTAG      EntityType = 5
+	EntityType_ENTITY_TYPE_CASHTAG      EntityType = 6
+	EntityType_ENTITY_TYPE_BOT_COMMAND  EntityType = 7
+)
+
+// Enum value maps for EntityType.
+var (
+	EntityType_name = map[int32]string{
+		0: "ENTITY_TYPE_UNSPECIFIED",
+		1: "ENTITY_TYPE_URL",
+		2: "ENTITY_TYPE_TEXT_URL",
+		3: "ENTITY_TYPE_MENTION",
+		4: "ENTITY_TYPE_MENTION_NAME",
+		5: "ENTITY_TYPE_HASHTAG",
+		6: "ENTITY_TYPE_CASHTAG",
+		7: "ENTITY_TYPE_BOT_COMMAND",
+	}
+	EntityType_value = map[string]int32{
+		"ENTITY_TYPE_UNSPECIFIED":  0,
+		"ENTITY_TYPE_URL":          1,
+		"ENTITY_TYPE_TEXT_URL":     2,
+		"ENTITY_TYPE_MENTION":      3,
+		"ENTITY_TYPE_MENTION_NAME": 4,
+		"ENTITY_TYPE_HASHTAG":      5,
+		"ENTITY_TYPE_CASHTAG":      6,
+		"ENTITY_TYPE_BOT_COMMAND":  7,
+	}
+)
+
+func (x EntityType) Enum() *EntityType {
+	p := new(EntityType)
+	*p = x
+	return p
+}
+
+func (x EntityType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EntityType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fetcher_fetch_proto_enumTypes[2].Descriptor()
+}
+
+func (EntityType) Type() protoreflect.EnumType {
+	return &file_proto_fetcher_fetch_proto_enumTypes[2]
+}
+
+func (x EntityType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EntityType.Descriptor instead.
+func (EntityType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{2}
+}
+
+type FetchErrorCode int32
+
+const (
+	FetchErrorCode_FETCH_ERROR_CODE_UNSPECIFIED  FetchErrorCode = 0
+	FetchErrorCode_FETCH_ERROR_CODE_INACCESSIBLE FetchErrorCode = 1
+	FetchErrorCode_FETCH_ERROR_CODE_NOT_FOUND    FetchErrorCode = 2
+	FetchErrorCode_FETCH_ERROR_CODE_RATE_LIMITED FetchErrorCode = 3
+	FetchErrorCode_FETCH_ERROR_CODE_INTERNAL     FetchErrorCode = 4
+	FetchErrorCode_FETCH_ERROR_CODE_UNSUPPORTED  FetchErrorCode = 5
+)
+
+// Enum value maps for FetchErrorCode.
+var (
+	FetchErrorCode_name = map[int32]string{
+		0: "FETCH_ERROR_CODE_UNSPECIFIED",
+		1: "FETCH_ERROR_CODE_INACCESSIBLE",
+		2: "FETCH_ERROR_CODE_NOT_FOUND",
+		3: "FETCH_ERROR_CODE_RATE_LIMITED",
+		4: "FETCH_ERROR_CODE_INTERNAL",
+		5: "FETCH_ERROR_CODE_UNSUPPORTED",
+	}
+	FetchErrorCode_value = map[string]int32{
+		"FETCH_ERROR_CODE_UNSPECIFIED":  0,
+		"FETCH_ERROR_CODE_INACCESSIBLE": 1,
+		"FETCH_ERROR_CODE_NOT_FOUND":    2,
+		"FETCH_ERROR_CODE_RATE_LIMITED": 3,
+		"FETCH_ERROR_CODE_INTERNAL":     4,
+		"FETCH_ERROR_CODE_UNSUPPORTED":  5,
+	}
+)
+
+func (x FetchErrorCode) Enum() *FetchErrorCode {
+	p := new(FetchErrorCode)
+	*p = x
+	return p
+}
+
+func (x FetchErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FetchErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fetcher_fetch_proto_enumTypes[3].Descriptor()
+}
+
+func (FetchErrorCode) Type() protoreflect.EnumType {
+	return &file_proto_fetcher_fetch_proto_enumTypes[3]
+}
+
+func (x FetchErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FetchErrorCode.Descriptor instead.
+func (FetchErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{3}
+}
+
+type JobStatus int32
+
+const (
+	JobStatus_JOB_STATUS_UNSPECIFIED JobStatus = 0
+	JobStatus_JOB_STATUS_PENDING     JobStatus = 1
+	JobStatus_JOB_STATUS_RUNNING     JobStatus = 2
+	JobStatus_JOB_STATUS_DONE        JobStatus = 3
+	JobStatus_JOB_STATUS_FAILED      JobStatus = 4
+	JobStatus_JOB_STATUS_CANCELLED   JobStatus = 5
+)
+
+// Enum value maps for JobStatus.
+var (
+	JobStatus_name = map[int32]string{
+		0: "JOB_STATUS_UNSPECIFIED",
+		1: "JOB_STATUS_PENDING",
+		2: "JOB_STATUS_RUNNING",
+		3: "JOB_STATUS_DONE",
+		4: "JOB_STATUS_FAILED",
+		5: "JOB_STATUS_CANCELLED",
+	}
+	JobStatus_value = map[string]int32{
+		"JOB_STATUS_UNSPECIFIED": 0,
+		"JOB_STATUS_PENDING":     1,
+		"JOB_STATUS_RUNNING":     2,
+		"JOB_STATUS_DONE":        3,
+		"JOB_STATUS_FAILED":      4,
+		"JOB_STATUS_CANCELLED":   5,
+	}
+)
+
+func (x JobStatus) Enum() *JobStatus {
+	p := new(JobStatus)
+	*p = x
+	return p
+}
+
+func (x JobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fetcher_fetch_proto_enumTypes[4].Descriptor()
+}
+
+func (JobStatus) Type() protoreflect.EnumType {
+	return &file_proto_fetcher_fetch_proto_enumTypes[4]
+}
+
+func (x JobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStatus.Descriptor instead.
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{4}
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -134,29 +427,33 @@ func (x *FetchRequest) GetSocial() bool {
 	return false
 }
 
-type Message struct {
+// Offset and length are measured in UTF-16 code units of original text
+type TextEntity struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
-	Ts            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=ts,proto3" json:"ts,omitempty"`
-	Link          string                 `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	Type          EntityType             `protobuf:"varint,1,opt,name=type,proto3,enum=fetcher.EntityType" json:"type,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int32                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Url           *string                `protobuf:"bytes,5,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	UserId        *int64                 `protobuf:"varint,6,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
+func (x *TextEntity) Reset() {
+	*x = TextEntity{}
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Message) String() string {
+func (x *TextEntity) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message) ProtoMessage() {}
+func (*TextEntity) ProtoMessage() {}
 
-func (x *Message) ProtoReflect() protoreflect.Message {
+func (x *TextEntity) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -168,53 +465,79 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
+// Deprecated: Use TextEntity.ProtoReflect.Descriptor instead.
+func (*TextEntity) Descriptor() ([]byte, []int) {
 	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Message) GetText() string {
+func (x *TextEntity) GetType() EntityType {
 	if x != nil {
-		return x.Text
+		return x.Type
 	}
-	return ""
+	return EntityType_ENTITY_TYPE_UNSPECIFIED
 }
 
-func (x *Message) GetTs() *timestamppb.Timestamp {
+func (x *TextEntity) GetOffset() int32 {
 	if x != nil {
-		return x.Ts
+		return x.Offset
 	}
-	return nil
+	return 0
 }
 
-func (x *Message) GetLink() string {
+func (x *TextEntity) GetLength() int32 {
 	if x != nil {
-		return x.Link
+		return x.Length
+	}
+	return 0
+}
+
+func (x *TextEntity) GetValue() string {
+	if x != nil {
+		return x.Value
 	}
 	return ""
 }
 
-type FetchResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TextEntity) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
 }
 
-func (x *FetchResponse) Reset() {
-	*x = FetchResponse{}
+func (x *TextEntity) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+type Sender struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Id:
+	//
+	//	*Sender_UserId
+	//	*Sender_ChatId
+	Id              isSender_Id `protobuf_oneof:"id"`
+	AuthorSignature string      `protobuf:"bytes,3,opt,name=author_signature,json=authorSignature,proto3" json:"author_signature,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Sender) Reset() {
+	*x = Sender{}
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FetchResponse) String() string {
+func (x *Sender) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FetchResponse) ProtoMessage() {}
+func (*Sender) ProtoMessage() {}
 
-func (x *FetchResponse) ProtoReflect() protoreflect.Message {
+func (x *Sender) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -226,39 +549,101 @@ func (x *FetchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FetchResponse.ProtoReflect.Descriptor instead.
-func (*FetchResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Sender.ProtoReflect.Descriptor instead.
+func (*Sender) Descriptor() ([]byte, []int) {
 	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *FetchResponse) GetMessages() []*Message {
+func (x *Sender) GetId() isSender_Id {
 	if x != nil {
-		return x.Messages
+		return x.Id
 	}
 	return nil
 }
 
-type SubscribeChatFolderRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ChatFolderLink string                 `protobuf:"bytes,1,opt,name=chat_folder_link,json=chatFolderLink,proto3" json:"chat_folder_link,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *Sender) GetUserId() int64 {
+	if x != nil {
+		if x, ok := x.Id.(*Sender_UserId); ok {
+			return x.UserId
+		}
+	}
+	return 0
 }
 
-func (x *SubscribeChatFolderRequest) Reset() {
-	*x = SubscribeChatFolderRequest{}
+func (x *Sender) GetChatId() int64 {
+	if x != nil {
+		if x, ok := x.Id.(*Sender_ChatId); ok {
+			return x.ChatId
+		}
+	}
+	return 0
+}
+
+func (x *Sender) GetAuthorSignature() string {
+	if x != nil {
+		return x.AuthorSignature
+	}
+	return ""
+}
+
+type isSender_Id interface {
+	isSender_Id()
+}
+
+type Sender_UserId struct {
+	UserId int64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3,oneof"`
+}
+
+type Sender_ChatId struct {
+	ChatId int64 `protobuf:"varint,2,opt,name=chat_id,json=chatId,proto3,oneof"`
+}
+
+func (*Sender_UserId) isSender_Id() {}
+
+func (*Sender_ChatId) isSender_Id() {}
+
+type Message struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Text             string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Ts               *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=ts,proto3" json:"ts,omitempty"`
+	Link             string                 `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	MediaType        MediaType              `protobuf:"varint,4,opt,name=media_type,json=mediaType,proto3,enum=fetcher.MediaType" json:"media_type,omitempty"`
+	MediaKey         *string                `protobuf:"bytes,5,opt,name=media_key,json=mediaKey,proto3,oneof" json:"media_key,omitempty"`
+	MediaMimeType    *string                `protobuf:"bytes,6,opt,name=media_mime_type,json=mediaMimeType,proto3,oneof" json:"media_mime_type,omitempty"`
+	ChatId           int64                  `protobuf:"varint,7,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId        int64                  `protobuf:"varint,8,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChannelUsername  string                 `protobuf:"bytes,9,opt,name=channel_username,json=channelUsername,proto3" json:"channel_username,omitempty"`
+	ChannelTitle     string                 `protobuf:"bytes,10,opt,name=channel_title,json=channelTitle,proto3" json:"channel_title,omitempty"`
+	Sender           *Sender                `protobuf:"bytes,11,opt,name=sender,proto3" json:"sender,omitempty"`
+	ViewCount        int32                  `protobuf:"varint,12,opt,name=view_count,json=viewCount,proto3" json:"view_count,omitempty"`
+	ForwardCount     int32                  `protobuf:"varint,13,opt,name=forward_count,json=forwardCount,proto3" json:"forward_count,omitempty"`
+	ReactionCount    int32                  `protobuf:"varint,14,opt,name=reaction_count,json=reactionCount,proto3" json:"reaction_count,omitempty"`
+	Reactions        map[string]int32       `protobuf:"bytes,15,rep,name=reactions,proto3" json:"reactions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ReplyToMessageId *int64                 `protobuf:"varint,16,opt,name=reply_to_message_id,json=replyToMessageId,proto3,oneof" json:"reply_to_message_id,omitempty"`
+	EditedAt         *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	ChangeType       ChangeType             `protobuf:"varint,18,opt,name=change_type,json=changeType,proto3,enum=fetcher.ChangeType" json:"change_type,omitempty"`
+	OriginalText     string                 `protobuf:"bytes,19,opt,name=original_text,json=originalText,proto3" json:"original_text,omitempty"`
+	Entities         []*TextEntity          `protobuf:"bytes,20,rep,name=entities,proto3" json:"entities,omitempty"`
+	// Set when text is a near-duplicate of an earlier message
+	DuplicateOf   *MessageRef `protobuf:"bytes,21,opt,name=duplicate_of,json=duplicateOf,proto3" json:"duplicate_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubscribeChatFolderRequest) String() string {
+func (x *Message) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeChatFolderRequest) ProtoMessage() {}
+func (*Message) ProtoMessage() {}
 
-func (x *SubscribeChatFolderRequest) ProtoReflect() protoreflect.Message {
+func (x *Message) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_fetcher_fetch_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -270,102 +655,1670 @@ func (x *SubscribeChatFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeChatFolderRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeChatFolderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
 	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *SubscribeChatFolderRequest) GetChatFolderLink() string {
+func (x *Message) GetText() string {
 	if x != nil {
-		return x.ChatFolderLink
+		return x.Text
 	}
 	return ""
 }
 
-var File_proto_fetcher_fetch_proto protoreflect.FileDescriptor
+func (x *Message) GetTs() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Ts
+	}
+	return nil
+}
 
-const file_proto_fetcher_fetch_proto_rawDesc = "" +
-	"\n" +
-	"\x19proto/fetcher/fetch.proto\x12\afetcher\x1a\x1fgoogle/protobuf/timestamp.proto\"\a\n" +
-	"\x05Empty\"\x8b\x02\n" +
-	"\fFetchRequest\x12\"\n" +
-	"\n" +
-	"request_id\x18\x01 \x01(\tH\x00R\trequestId\x88\x01\x01\x12(\n" +
-	"\x10chat_folder_link\x18\x02 \x01(\tR\x0echatFolderLink\x12;\n" +
-	"\vright_bound\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"rightBound\x129\n" +
-	"\n" +
-	"left_bound\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tleftBound\x12\x1b\n" +
-	"\x06social\x18\x05 \x01(\bH\x01R\x06social\x88\x01\x01B\r\n" +
-	"\v_request_idB\t\n" +
-	"\a_social\"]\n" +
-	"\aMessage\x12\x12\n" +
-	"\x04text\x18\x01 \x01(\tR\x04text\x12*\n" +
-	"\x02ts\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02ts\x12\x12\n" +
-	"\x04link\x18\x03 \x01(\tR\x04link\"=\n" +
-	"\rFetchResponse\x12,\n" +
-	"\bmessages\x18\x01 \x03(\v2\x10.fetcher.MessageR\bmessages\"F\n" +
-	"\x1aSubscribeChatFolderRequest\x12(\n" +
-	"\x10chat_folder_link\x18\x01 \x01(\tR\x0echatFolderLink2\x92\x01\n" +
-	"\x0eFetcherService\x128\n" +
-	"\x05Fetch\x12\x15.fetcher.FetchRequest\x1a\x16.fetcher.FetchResponse\"\x00\x12F\n" +
-	"\rSubscribeChat\x12#.fetcher.SubscribeChatFolderRequest\x1a\x0e.fetcher.Empty\"\x00B/Z-github.com/nrydanov/inbrief/gen/proto/fetcherb\x06proto3"
+func (x *Message) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
 
-var (
-	file_proto_fetcher_fetch_proto_rawDescOnce sync.Once
-	file_proto_fetcher_fetch_proto_rawDescData []byte
-)
+func (x *Message) GetMediaType() MediaType {
+	if x != nil {
+		return x.MediaType
+	}
+	return MediaType_MEDIA_TYPE_UNSPECIFIED
+}
 
-func file_proto_fetcher_fetch_proto_rawDescGZIP() []byte {
-	file_proto_fetcher_fetch_proto_rawDescOnce.Do(func() {
-		file_proto_fetcher_fetch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_fetcher_fetch_proto_rawDesc), len(file_proto_fetcher_fetch_proto_rawDesc)))
-	})
-	return file_proto_fetcher_fetch_proto_rawDescData
+func (x *Message) GetMediaKey() string {
+	if x != nil && x.MediaKey != nil {
+		return *x.MediaKey
+	}
+	return ""
 }
 
-var file_proto_fetcher_fetch_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_proto_fetcher_fetch_proto_goTypes = []any{
-	(*Empty)(nil),                      // 0: fetcher.Empty
-	(*FetchRequest)(nil),               // 1: fetcher.FetchRequest
-	(*Message)(nil),                    // 2: fetcher.Message
-	(*FetchResponse)(nil),              // 3: fetcher.FetchResponse
-	(*SubscribeChatFolderRequest)(nil), // 4: fetcher.SubscribeChatFolderRequest
-	(*timestamppb.Timestamp)(nil),      // 5: google.protobuf.Timestamp
+func (x *Message) GetMediaMimeType() string {
+	if x != nil && x.MediaMimeType != nil {
+		return *x.MediaMimeType
+	}
+	return ""
 }
-var file_proto_fetcher_fetch_proto_depIdxs = []int32{
-	5, // 0: fetcher.FetchRequest.right_bound:type_name -> google.protobuf.Timestamp
-	5, // 1: fetcher.FetchRequest.left_bound:type_name -> google.protobuf.Timestamp
-	5, // 2: fetcher.Message.ts:type_name -> google.protobuf.Timestamp
-	2, // 3: fetcher.FetchResponse.messages:type_name -> fetcher.Message
-	1, // 4: fetcher.FetcherService.Fetch:input_type -> fetcher.FetchRequest
-	4, // 5: fetcher.FetcherService.SubscribeChat:input_type -> fetcher.SubscribeChatFolderRequest
-	3, // 6: fetcher.FetcherService.Fetch:output_type -> fetcher.FetchResponse
-	0, // 7: fetcher.FetcherService.SubscribeChat:output_type -> fetcher.Empty
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+func (x *Message) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
 }
 
-func init() { file_proto_fetcher_fetch_proto_init() }
+func (x *Message) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *Message) GetChannelUsername() string {
+	if x != nil {
+		return x.ChannelUsername
+	}
+	return ""
+}
+
+func (x *Message) GetChannelTitle() string {
+	if x != nil {
+		return x.ChannelTitle
+	}
+	return ""
+}
+
+func (x *Message) GetSender() *Sender {
+	if x != nil {
+		return x.Sender
+	}
+	return nil
+}
+
+func (x *Message) GetViewCount() int32 {
+	if x != nil {
+		return x.ViewCount
+	}
+	return 0
+}
+
+func (x *Message) GetForwardCount() int32 {
+	if x != nil {
+		return x.ForwardCount
+	}
+	return 0
+}
+
+func (x *Message) GetReactionCount() int32 {
+	if x != nil {
+		return x.ReactionCount
+	}
+	return 0
+}
+
+func (x *Message) GetReactions() map[string]int32 {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *Message) GetReplyToMessageId() int64 {
+	if x != nil && x.ReplyToMessageId != nil {
+		return *x.ReplyToMessageId
+	}
+	return 0
+}
+
+func (x *Message) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *Message) GetChangeType() ChangeType {
+	if x != nil {
+		return x.ChangeType
+	}
+	return ChangeType_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *Message) GetOriginalText() string {
+	if x != nil {
+		return x.OriginalText
+	}
+	return ""
+}
+
+func (x *Message) GetEntities() []*TextEntity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *Message) GetDuplicateOf() *MessageRef {
+	if x != nil {
+		return x.DuplicateOf
+	}
+	return nil
+}
+
+type MessageRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        int64                  `protobuf:"varint,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageRef) Reset() {
+	*x = MessageRef{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageRef) ProtoMessage() {}
+
+func (x *MessageRef) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageRef.ProtoReflect.Descriptor instead.
+func (*MessageRef) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MessageRef) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *MessageRef) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+type ChannelStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        int64                  `protobuf:"varint,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	MessageCount  int32                  `protobuf:"varint,3,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	ErrorCode     *FetchErrorCode        `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3,enum=fetcher.FetchErrorCode,oneof" json:"error_code,omitempty"`
+	ErrorMessage  *string                `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3,oneof" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelStatus) Reset() {
+	*x = ChannelStatus{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelStatus) ProtoMessage() {}
+
+func (x *ChannelStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelStatus.ProtoReflect.Descriptor instead.
+func (*ChannelStatus) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChannelStatus) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *ChannelStatus) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChannelStatus) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *ChannelStatus) GetErrorCode() FetchErrorCode {
+	if x != nil && x.ErrorCode != nil {
+		return *x.ErrorCode
+	}
+	return FetchErrorCode_FETCH_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *ChannelStatus) GetErrorMessage() string {
+	if x != nil && x.ErrorMessage != nil {
+		return *x.ErrorMessage
+	}
+	return ""
+}
+
+type FetchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	Channels      []*ChannelStatus       `protobuf:"bytes,2,rep,name=channels,proto3" json:"channels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchResponse) Reset() {
+	*x = FetchResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchResponse) ProtoMessage() {}
+
+func (x *FetchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchResponse.ProtoReflect.Descriptor instead.
+func (*FetchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FetchResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *FetchResponse) GetChannels() []*ChannelStatus {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type MessageChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        int64                  `protobuf:"varint,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Messages      []*Message             `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageChunk) Reset() {
+	*x = MessageChunk{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageChunk) ProtoMessage() {}
+
+func (x *MessageChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageChunk.ProtoReflect.Descriptor instead.
+func (*MessageChunk) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MessageChunk) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *MessageChunk) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type FetchProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        int64                  `protobuf:"varint,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	ChannelsDone  int32                  `protobuf:"varint,2,opt,name=channels_done,json=channelsDone,proto3" json:"channels_done,omitempty"`
+	ChannelsTotal int32                  `protobuf:"varint,3,opt,name=channels_total,json=channelsTotal,proto3" json:"channels_total,omitempty"`
+	Status        *ChannelStatus         `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchProgress) Reset() {
+	*x = FetchProgress{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchProgress) ProtoMessage() {}
+
+func (x *FetchProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchProgress.ProtoReflect.Descriptor instead.
+func (*FetchProgress) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FetchProgress) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *FetchProgress) GetChannelsDone() int32 {
+	if x != nil {
+		return x.ChannelsDone
+	}
+	return 0
+}
+
+func (x *FetchProgress) GetChannelsTotal() int32 {
+	if x != nil {
+		return x.ChannelsTotal
+	}
+	return 0
+}
+
+func (x *FetchProgress) GetStatus() *ChannelStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type FetchStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*FetchStreamResponse_Chunk
+	//	*FetchStreamResponse_Progress
+	Payload       isFetchStreamResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchStreamResponse) Reset() {
+	*x = FetchStreamResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchStreamResponse) ProtoMessage() {}
+
+func (x *FetchStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchStreamResponse.ProtoReflect.Descriptor instead.
+func (*FetchStreamResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FetchStreamResponse) GetPayload() isFetchStreamResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *FetchStreamResponse) GetChunk() *MessageChunk {
+	if x != nil {
+		if x, ok := x.Payload.(*FetchStreamResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+func (x *FetchStreamResponse) GetProgress() *FetchProgress {
+	if x != nil {
+		if x, ok := x.Payload.(*FetchStreamResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+type isFetchStreamResponse_Payload interface {
+	isFetchStreamResponse_Payload()
+}
+
+type FetchStreamResponse_Chunk struct {
+	Chunk *MessageChunk `protobuf:"bytes,1,opt,name=chunk,proto3,oneof"`
+}
+
+type FetchStreamResponse_Progress struct {
+	Progress *FetchProgress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+func (*FetchStreamResponse_Chunk) isFetchStreamResponse_Payload() {}
+
+func (*FetchStreamResponse_Progress) isFetchStreamResponse_Payload() {}
+
+// Published to Redis after every batch is written. Version is incremented on
+// incompatible changes.
+type BatchNotification struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	BatchId string                 `protobuf:"bytes,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	// Sink the batch was written to, i.e. s3, local or postgres
+	Sink string `protobuf:"bytes,3,opt,name=sink,proto3" json:"sink,omitempty"`
+	// S3 bucket, directory or table depending on the sink
+	Bucket       string                 `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Key          string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	Format       string                 `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	MessageCount int32                  `protobuf:"varint,7,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	MinTs        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=min_ts,json=minTs,proto3" json:"min_ts,omitempty"`
+	MaxTs        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=max_ts,json=maxTs,proto3" json:"max_ts,omitempty"`
+	// stream or fetch
+	Source string `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`
+	// sha256:<hex> of the written object
+	Checksum      string                 `protobuf:"bytes,11,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchNotification) Reset() {
+	*x = BatchNotification{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchNotification) ProtoMessage() {}
+
+func (x *BatchNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchNotification.ProtoReflect.Descriptor instead.
+func (*BatchNotification) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchNotification) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *BatchNotification) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetMessageCount() int32 {
+	if x != nil {
+		return x.MessageCount
+	}
+	return 0
+}
+
+func (x *BatchNotification) GetMinTs() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MinTs
+	}
+	return nil
+}
+
+func (x *BatchNotification) GetMaxTs() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaxTs
+	}
+	return nil
+}
+
+func (x *BatchNotification) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *BatchNotification) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Messages of every channel are written page by page to configured sinks
+// under <result_prefix><chat_id>/<page>.<format>, relative to the sink
+// location (S3 prefix, local directory). Postgres rows get it as batch_id.
+type FetchJob struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	JobId             string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status            JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=fetcher.JobStatus" json:"status,omitempty"`
+	ChatFolderLink    string                 `protobuf:"bytes,3,opt,name=chat_folder_link,json=chatFolderLink,proto3" json:"chat_folder_link,omitempty"`
+	LeftBound         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=left_bound,json=leftBound,proto3" json:"left_bound,omitempty"`
+	RightBound        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=right_bound,json=rightBound,proto3" json:"right_bound,omitempty"`
+	ChannelsDone      int32                  `protobuf:"varint,6,opt,name=channels_done,json=channelsDone,proto3" json:"channels_done,omitempty"`
+	ChannelsTotal     int32                  `protobuf:"varint,7,opt,name=channels_total,json=channelsTotal,proto3" json:"channels_total,omitempty"`
+	MessagesCollected int64                  `protobuf:"varint,8,opt,name=messages_collected,json=messagesCollected,proto3" json:"messages_collected,omitempty"`
+	ResultPrefix      string                 `protobuf:"bytes,9,opt,name=result_prefix,json=resultPrefix,proto3" json:"result_prefix,omitempty"`
+	Channels          []*ChannelStatus       `protobuf:"bytes,10,rep,name=channels,proto3" json:"channels,omitempty"`
+	Error             *string                `protobuf:"bytes,11,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FetchJob) Reset() {
+	*x = FetchJob{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchJob) ProtoMessage() {}
+
+func (x *FetchJob) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchJob.ProtoReflect.Descriptor instead.
+func (*FetchJob) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FetchJob) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *FetchJob) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *FetchJob) GetChatFolderLink() string {
+	if x != nil {
+		return x.ChatFolderLink
+	}
+	return ""
+}
+
+func (x *FetchJob) GetLeftBound() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LeftBound
+	}
+	return nil
+}
+
+func (x *FetchJob) GetRightBound() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RightBound
+	}
+	return nil
+}
+
+func (x *FetchJob) GetChannelsDone() int32 {
+	if x != nil {
+		return x.ChannelsDone
+	}
+	return 0
+}
+
+func (x *FetchJob) GetChannelsTotal() int32 {
+	if x != nil {
+		return x.ChannelsTotal
+	}
+	return 0
+}
+
+func (x *FetchJob) GetMessagesCollected() int64 {
+	if x != nil {
+		return x.MessagesCollected
+	}
+	return 0
+}
+
+func (x *FetchJob) GetResultPrefix() string {
+	if x != nil {
+		return x.ResultPrefix
+	}
+	return ""
+}
+
+func (x *FetchJob) GetChannels() []*ChannelStatus {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *FetchJob) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *FetchJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *FetchJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type StartFetchJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *FetchJob              `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartFetchJobResponse) Reset() {
+	*x = StartFetchJobResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartFetchJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartFetchJobResponse) ProtoMessage() {}
+
+func (x *StartFetchJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartFetchJobResponse.ProtoReflect.Descriptor instead.
+func (*StartFetchJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StartFetchJobResponse) GetJob() *FetchJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetFetchJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFetchJobRequest) Reset() {
+	*x = GetFetchJobRequest{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFetchJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFetchJobRequest) ProtoMessage() {}
+
+func (x *GetFetchJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFetchJobRequest.ProtoReflect.Descriptor instead.
+func (*GetFetchJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetFetchJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetFetchJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *FetchJob              `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFetchJobResponse) Reset() {
+	*x = GetFetchJobResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFetchJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFetchJobResponse) ProtoMessage() {}
+
+func (x *GetFetchJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFetchJobResponse.ProtoReflect.Descriptor instead.
+func (*GetFetchJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetFetchJobResponse) GetJob() *FetchJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type CancelFetchJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelFetchJobRequest) Reset() {
+	*x = CancelFetchJobRequest{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelFetchJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelFetchJobRequest) ProtoMessage() {}
+
+func (x *CancelFetchJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelFetchJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelFetchJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelFetchJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CancelFetchJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *FetchJob              `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelFetchJobResponse) Reset() {
+	*x = CancelFetchJobResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelFetchJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelFetchJobResponse) ProtoMessage() {}
+
+func (x *CancelFetchJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelFetchJobResponse.ProtoReflect.Descriptor instead.
+func (*CancelFetchJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelFetchJobResponse) GetJob() *FetchJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type SubscribeChatFolderRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatFolderLink string                 `protobuf:"bytes,1,opt,name=chat_folder_link,json=chatFolderLink,proto3" json:"chat_folder_link,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SubscribeChatFolderRequest) Reset() {
+	*x = SubscribeChatFolderRequest{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeChatFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeChatFolderRequest) ProtoMessage() {}
+
+func (x *SubscribeChatFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeChatFolderRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeChatFolderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubscribeChatFolderRequest) GetChatFolderLink() string {
+	if x != nil {
+		return x.ChatFolderLink
+	}
+	return ""
+}
+
+type Subscription struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatFolderLink string                 `protobuf:"bytes,1,opt,name=chat_folder_link,json=chatFolderLink,proto3" json:"chat_folder_link,omitempty"`
+	ChatIds        []int64                `protobuf:"varint,2,rep,packed,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	Usernames      []string               `protobuf:"bytes,3,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Subscription) GetChatFolderLink() string {
+	if x != nil {
+		return x.ChatFolderLink
+	}
+	return ""
+}
+
+func (x *Subscription) GetChatIds() []int64 {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+func (x *Subscription) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type UnsubscribeChatFolderRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatFolderLink string                 `protobuf:"bytes,1,opt,name=chat_folder_link,json=chatFolderLink,proto3" json:"chat_folder_link,omitempty"`
+	LeaveChats     bool                   `protobuf:"varint,2,opt,name=leave_chats,json=leaveChats,proto3" json:"leave_chats,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UnsubscribeChatFolderRequest) Reset() {
+	*x = UnsubscribeChatFolderRequest{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeChatFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeChatFolderRequest) ProtoMessage() {}
+
+func (x *UnsubscribeChatFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeChatFolderRequest.ProtoReflect.Descriptor instead.
+func (*UnsubscribeChatFolderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UnsubscribeChatFolderRequest) GetChatFolderLink() string {
+	if x != nil {
+		return x.ChatFolderLink
+	}
+	return ""
+}
+
+func (x *UnsubscribeChatFolderRequest) GetLeaveChats() bool {
+	if x != nil {
+		return x.LeaveChats
+	}
+	return false
+}
+
+type UnsubscribeChatFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnsubscribeChatFolderResponse) Reset() {
+	*x = UnsubscribeChatFolderResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeChatFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeChatFolderResponse) ProtoMessage() {}
+
+func (x *UnsubscribeChatFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeChatFolderResponse.ProtoReflect.Descriptor instead.
+func (*UnsubscribeChatFolderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UnsubscribeChatFolderResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{22}
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fetcher_fetch_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fetcher_fetch_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+var File_proto_fetcher_fetch_proto protoreflect.FileDescriptor
+
+const file_proto_fetcher_fetch_proto_rawDesc = "" +
+	"\n" +
+	"\x19proto/fetcher/fetch.proto\x12\afetcher\x1a\x1fgoogle/protobuf/timestamp.proto\"\a\n" +
+	"\x05Empty\"\x8b\x02\n" +
+	"\fFetchRequest\x12\"\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tH\x00R\trequestId\x88\x01\x01\x12(\n" +
+	"\x10chat_folder_link\x18\x02 \x01(\tR\x0echatFolderLink\x12;\n" +
+	"\vright_bound\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"rightBound\x129\n" +
+	"\n" +
+	"left_bound\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tleftBound\x12\x1b\n" +
+	"\x06social\x18\x05 \x01(\bH\x01R\x06social\x88\x01\x01B\r\n" +
+	"\v_request_idB\t\n" +
+	"\a_social\"\xc4\x01\n" +
+	"\n" +
+	"TextEntity\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.fetcher.EntityTypeR\x04type\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12\x15\n" +
+	"\x03url\x18\x05 \x01(\tH\x00R\x03url\x88\x01\x01\x12\x1c\n" +
+	"\auser_id\x18\x06 \x01(\x03H\x01R\x06userId\x88\x01\x01B\x06\n" +
+	"\x04_urlB\n" +
+	"\n" +
+	"\b_user_id\"o\n" +
+	"\x06Sender\x12\x19\n" +
+	"\auser_id\x18\x01 \x01(\x03H\x00R\x06userId\x12\x19\n" +
+	"\achat_id\x18\x02 \x01(\x03H\x00R\x06chatId\x12)\n" +
+	"\x10author_signature\x18\x03 \x01(\tR\x0fauthorSignatureB\x04\n" +
+	"\x02id\"\xe3\a\n" +
+	"\aMessage\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12*\n" +
+	"\x02ts\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02ts\x12\x12\n" +
+	"\x04link\x18\x03 \x01(\tR\x04link\x121\n" +
+	"\n" +
+	"media_type\x18\x04 \x01(\x0e2\x12.fetcher.MediaTypeR\tmediaType\x12 \n" +
+	"\tmedia_key\x18\x05 \x01(\tH\x00R\bmediaKey\x88\x01\x01\x12+\n" +
+	"\x0fmedia_mime_type\x18\x06 \x01(\tH\x01R\rmediaMimeType\x88\x01\x01\x12\x17\n" +
+	"\achat_id\x18\a \x01(\x03R\x06chatId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\b \x01(\x03R\tmessageId\x12)\n" +
+	"\x10channel_username\x18\t \x01(\tR\x0fchannelUsername\x12#\n" +
+	"\rchannel_title\x18\n" +
+	" \x01(\tR\fchannelTitle\x12'\n" +
+	"\x06sender\x18\v \x01(\v2\x0f.fetcher.SenderR\x06sender\x12\x1d\n" +
+	"\n" +
+	"view_count\x18\f \x01(\x05R\tviewCount\x12#\n" +
+	"\rforward_count\x18\r \x01(\x05R\fforwardCount\x12%\n" +
+	"\x0ereaction_count\x18\x0e \x01(\x05R\rreactionCount\x12=\n" +
+	"\treactions\x18\x0f \x03(\v2\x1f.fetcher.Message.ReactionsEntryR\treactions\x122\n" +
+	"\x13reply_to_message_id\x18\x10 \x01(\x03H\x02R\x10replyToMessageId\x88\x01\x01\x127\n" +
+	"\tedited_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x124\n" +
+	"\vchange_type\x18\x12 \x01(\x0e2\x13.fetcher.ChangeTypeR\n" +
+	"changeType\x12#\n" +
+	"\roriginal_text\x18\x13 \x01(\tR\foriginalText\x12/\n" +
+	"\bentities\x18\x14 \x03(\v2\x13.fetcher.TextEntityR\bentities\x126\n" +
+	"\fduplicate_of\x18\x15 \x01(\v2\x13.fetcher.MessageRefR\vduplicateOf\x1a<\n" +
+	"\x0eReactionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01B\f\n" +
+	"\n" +
+	"_media_keyB\x12\n" +
+	"\x10_media_mime_typeB\x16\n" +
+	"\x14_reply_to_message_id\"D\n" +
+	"\n" +
+	"MessageRef\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\x03R\x06chatId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\"\xf1\x01\n" +
+	"\rChannelStatus\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\x03R\x06chatId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12#\n" +
+	"\rmessage_count\x18\x03 \x01(\x05R\fmessageCount\x12;\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\x0e2\x17.fetcher.FetchErrorCodeH\x00R\terrorCode\x88\x01\x01\x12(\n" +
+	"\rerror_message\x18\x05 \x01(\tH\x01R\ferrorMessage\x88\x01\x01B\r\n" +
+	"\v_error_codeB\x10\n" +
+	"\x0e_error_message\"q\n" +
+	"\rFetchResponse\x12,\n" +
+	"\bmessages\x18\x01 \x03(\v2\x10.fetcher.MessageR\bmessages\x122\n" +
+	"\bchannels\x18\x02 \x03(\v2\x16.fetcher.ChannelStatusR\bchannels\"U\n" +
+	"\fMessageChunk\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\x03R\x06chatId\x12,\n" +
+	"\bmessages\x18\x02 \x03(\v2\x10.fetcher.MessageR\bmessages\"\xa4\x01\n" +
+	"\rFetchProgress\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\x03R\x06chatId\x12#\n" +
+	"\rchannels_done\x18\x02 \x01(\x05R\fchannelsDone\x12%\n" +
+	"\x0echannels_total\x18\x03 \x01(\x05R\rchannelsTotal\x12.\n" +
+	"\x06status\x18\x04 \x01(\v2\x16.fetcher.ChannelStatusR\x06status\"\x85\x01\n" +
+	"\x13FetchStreamResponse\x12-\n" +
+	"\x05chunk\x18\x01 \x01(\v2\x15.fetcher.MessageChunkH\x00R\x05chunk\x124\n" +
+	"\bprogress\x18\x02 \x01(\v2\x16.fetcher.FetchProgressH\x00R\bprogressB\t\n" +
+	"\apayload\"\x98\x03\n" +
+	"\x11BatchNotification\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x12\n" +
+	"\x04sink\x18\x03 \x01(\tR\x04sink\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucket\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\x12\x16\n" +
+	"\x06format\x18\x06 \x01(\tR\x06format\x12#\n" +
+	"\rmessage_count\x18\a \x01(\x05R\fmessageCount\x121\n" +
+	"\x06min_ts\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x05minTs\x121\n" +
+	"\x06max_ts\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x05maxTs\x12\x16\n" +
+	"\x06source\x18\n" +
+	" \x01(\tR\x06source\x12\x1a\n" +
+	"\bchecksum\x18\v \x01(\tR\bchecksum\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xde\x04\n" +
+	"\bFetchJob\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.fetcher.JobStatusR\x06status\x12(\n" +
+	"\x10chat_folder_link\x18\x03 \x01(\tR\x0echatFolderLink\x129\n" +
+	"\n" +
+	"left_bound\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tleftBound\x12;\n" +
+	"\vright_bound\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"rightBound\x12#\n" +
+	"\rchannels_done\x18\x06 \x01(\x05R\fchannelsDone\x12%\n" +
+	"\x0echannels_total\x18\a \x01(\x05R\rchannelsTotal\x12-\n" +
+	"\x12messages_collected\x18\b \x01(\x03R\x11messagesCollected\x12#\n" +
+	"\rresult_prefix\x18\t \x01(\tR\fresultPrefix\x122\n" +
+	"\bchannels\x18\n" +
+	" \x03(\v2\x16.fetcher.ChannelStatusR\bchannels\x12\x19\n" +
+	"\x05error\x18\v \x01(\tH\x00R\x05error\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\b\n" +
+	"\x06_error\"<\n" +
+	"\x15StartFetchJobResponse\x12#\n" +
+	"\x03job\x18\x01 \x01(\v2\x11.fetcher.FetchJobR\x03job\"+\n" +
+	"\x12GetFetchJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\":\n" +
+	"\x13GetFetchJobResponse\x12#\n" +
+	"\x03job\x18\x01 \x01(\v2\x11.fetcher.FetchJobR\x03job\".\n" +
+	"\x15CancelFetchJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"=\n" +
+	"\x16CancelFetchJobResponse\x12#\n" +
+	"\x03job\x18\x01 \x01(\v2\x11.fetcher.FetchJobR\x03job\"F\n" +
+	"\x1aSubscribeChatFolderRequest\x12(\n" +
+	"\x10chat_folder_link\x18\x01 \x01(\tR\x0echatFolderLink\"\xac\x01\n" +
+	"\fSubscription\x12(\n" +
+	"\x10chat_folder_link\x18\x01 \x01(\tR\x0echatFolderLink\x12\x19\n" +
+	"\bchat_ids\x18\x02 \x03(\x03R\achatIds\x12\x1c\n" +
+	"\tusernames\x18\x03 \x03(\tR\tusernames\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"i\n" +
+	"\x1cUnsubscribeChatFolderRequest\x12(\n" +
+	"\x10chat_folder_link\x18\x01 \x01(\tR\x0echatFolderLink\x12\x1f\n" +
+	"\vleave_chats\x18\x02 \x01(\bR\n" +
+	"leaveChats\"Z\n" +
+	"\x1dUnsubscribeChatFolderResponse\x129\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x15.fetcher.SubscriptionR\fsubscription\"\x1a\n" +
+	"\x18ListSubscriptionsRequest\"X\n" +
+	"\x19ListSubscriptionsResponse\x12;\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x15.fetcher.SubscriptionR\rsubscriptions*\xb1\x01\n" +
+	"\tMediaType\x12\x1a\n" +
+	"\x16MEDIA_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fMEDIA_TYPE_TEXT\x10\x01\x12\x14\n" +
+	"\x10MEDIA_TYPE_PHOTO\x10\x02\x12\x14\n" +
+	"\x10MEDIA_TYPE_VIDEO\x10\x03\x12\x17\n" +
+	"\x13MEDIA_TYPE_DOCUMENT\x10\x04\x12\x18\n" +
+	"\x14MEDIA_TYPE_ANIMATION\x10\x05\x12\x14\n" +
+	"\x10MEDIA_TYPE_AUDIO\x10\x06*o\n" +
+	"\n" +
+	"ChangeType\x12\x1b\n" +
+	"\x17CHANGE_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fCHANGE_TYPE_NEW\x10\x01\x12\x16\n" +
+	"\x12CHANGE_TYPE_EDITED\x10\x02\x12\x17\n" +
+	"\x13CHANGE_TYPE_DELETED\x10\x03*\xde\x01\n" +
+	"\n" +
+	"EntityType\x12\x1b\n" +
+	"\x17ENTITY_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fENTITY_TYPE_URL\x10\x01\x12\x18\n" +
+	"\x14ENTITY_TYPE_TEXT_URL\x10\x02\x12\x17\n" +
+	"\x13ENTITY_TYPE_MENTION\x10\x03\x12\x1c\n" +
+	"\x18ENTITY_TYPE_MENTION_NAME\x10\x04\x12\x17\n" +
+	"\x13ENTITY_TYPE_HASHTAG\x10\x05\x12\x17\n" +
+	"\x13ENTITY_TYPE_CASHTAG\x10\x06\x12\x1b\n" +
+	"\x17ENTITY_TYPE_BOT_COMMAND\x10\a*\xd9\x01\n" +
+	"\x0eFetchErrorCode\x12 \n" +
+	"\x1cFETCH_ERROR_CODE_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dFETCH_ERROR_CODE_INACCESSIBLE\x10\x01\x12\x1e\n" +
+	"\x1aFETCH_ERROR_CODE_NOT_FOUND\x10\x02\x12!\n" +
+	"\x1dFETCH_ERROR_CODE_RATE_LIMITED\x10\x03\x12\x1d\n" +
+	"\x19FETCH_ERROR_CODE_INTERNAL\x10\x04\x12 \n" +
+	"\x1cFETCH_ERROR_CODE_UNSUPPORTED\x10\x05*\x9d\x01\n" +
+	"\tJobStatus\x12\x1a\n" +
+	"\x16JOB_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12JOB_STATUS_PENDING\x10\x01\x12\x16\n" +
+	"\x12JOB_STATUS_RUNNING\x10\x02\x12\x13\n" +
+	"\x0fJOB_STATUS_DONE\x10\x03\x12\x15\n" +
+	"\x11JOB_STATUS_FAILED\x10\x04\x12\x18\n" +
+	"\x14JOB_STATUS_CANCELLED\x10\x052\x8d\x05\n" +
+	"\x0eFetcherService\x128\n" +
+	"\x05Fetch\x12\x15.fetcher.FetchRequest\x1a\x16.fetcher.FetchResponse\"\x00\x12F\n" +
+	"\vFetchStream\x12\x15.fetcher.FetchRequest\x1a\x1c.fetcher.FetchStreamResponse\"\x000\x01\x12H\n" +
+	"\rStartFetchJob\x12\x15.fetcher.FetchRequest\x1a\x1e.fetcher.StartFetchJobResponse\"\x00\x12J\n" +
+	"\vGetFetchJob\x12\x1b.fetcher.GetFetchJobRequest\x1a\x1c.fetcher.GetFetchJobResponse\"\x00\x12S\n" +
+	"\x0eCancelFetchJob\x12\x1e.fetcher.CancelFetchJobRequest\x1a\x1f.fetcher.CancelFetchJobResponse\"\x00\x12F\n" +
+	"\rSubscribeChat\x12#.fetcher.SubscribeChatFolderRequest\x1a\x0e.fetcher.Empty\"\x00\x12h\n" +
+	"\x15UnsubscribeChatFolder\x12%.fetcher.UnsubscribeChatFolderRequest\x1a&.fetcher.UnsubscribeChatFolderResponse\"\x00\x12\\\n" +
+	"\x11ListSubscriptions\x12!.fetcher.ListSubscriptionsRequest\x1a\".fetcher.ListSubscriptionsResponse\"\x00B/Z-github.com/nrydanov/inbrief/gen/proto/fetcherb\x06proto3"
+
+var (
+	file_proto_fetcher_fetch_proto_rawDescOnce sync.Once
+	file_proto_fetcher_fetch_proto_rawDescData []byte
+)
+
+func file_proto_fetcher_fetch_proto_rawDescGZIP() []byte {
+	file_proto_fetcher_fetch_proto_rawDescOnce.Do(func() {
+		file_proto_fetcher_fetch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_fetcher_fetch_proto_rawDesc), len(file_proto_fetcher_fetch_proto_rawDesc)))
+	})
+	return file_proto_fetcher_fetch_proto_rawDescData
+}
+
+var file_proto_fetcher_fetch_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_fetcher_fetch_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_proto_fetcher_fetch_proto_goTypes = []any{
+	(MediaType)(0),                        // 0: fetcher.MediaType
+	(ChangeType)(0),                       // 1: fetcher.ChangeType
+	(EntityType)(0),                       // 2: fetcher.EntityType
+	(FetchErrorCode)(0),                   // 3: fetcher.FetchErrorCode
+	(JobStatus)(0),                        // 4: fetcher.JobStatus
+	(*Empty)(nil),                         // 5: fetcher.Empty
+	(*FetchRequest)(nil),                  // 6: fetcher.FetchRequest
+	(*TextEntity)(nil),                    // 7: fetcher.TextEntity
+	(*Sender)(nil),                        // 8: fetcher.Sender
+	(*Message)(nil),                       // 9: fetcher.Message
+	(*MessageRef)(nil),                    // 10: fetcher.MessageRef
+	(*ChannelStatus)(nil),                 // 11: fetcher.ChannelStatus
+	(*FetchResponse)(nil),                 // 12: fetcher.FetchResponse
+	(*MessageChunk)(nil),                  // 13: fetcher.MessageChunk
+	(*FetchProgress)(nil),                 // 14: fetcher.FetchProgress
+	(*FetchStreamResponse)(nil),           // 15: fetcher.FetchStreamResponse
+	(*BatchNotification)(nil),             // 16: fetcher.BatchNotification
+	(*FetchJob)(nil),                      // 17: fetcher.FetchJob
+	(*StartFetchJobResponse)(nil),         // 18: fetcher.StartFetchJobResponse
+	(*GetFetchJobRequest)(nil),            // 19: fetcher.GetFetchJobRequest
+	(*GetFetchJobResponse)(nil),           // 20: fetcher.GetFetchJobResponse
+	(*CancelFetchJobRequest)(nil),         // 21: fetcher.CancelFetchJobRequest
+	(*CancelFetchJobResponse)(nil),        // 22: fetcher.CancelFetchJobResponse
+	(*SubscribeChatFolderRequest)(nil),    // 23: fetcher.SubscribeChatFolderRequest
+	(*Subscription)(nil),                  // 24: fetcher.Subscription
+	(*UnsubscribeChatFolderRequest)(nil),  // 25: fetcher.UnsubscribeChatFolderRequest
+	(*UnsubscribeChatFolderResponse)(nil), // 26: fetcher.UnsubscribeChatFolderResponse
+	(*ListSubscriptionsRequest)(nil),      // 27: fetcher.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),     // 28: fetcher.ListSubscriptionsResponse
+	nil,                                   // 29: fetcher.Message.ReactionsEntry
+	(*timestamppb.Timestamp)(nil),         // 30: google.protobuf.Timestamp
+}
+var file_proto_fetcher_fetch_proto_depIdxs = []int32{
+	30, // 0: fetcher.FetchRequest.right_bound:type_name -> google.protobuf.Timestamp
+	30, // 1: fetcher.FetchRequest.left_bound:type_name -> google.protobuf.Timestamp
+	2,  // 2: fetcher.TextEntity.type:type_name -> fetcher.EntityType
+	30, // 3: fetcher.Message.ts:type_name -> google.protobuf.Timestamp
+	0,  // 4: fetcher.Message.media_type:type_name -> fetcher.MediaType
+	8,  // 5: fetcher.Message.sender:type_name -> fetcher.Sender
+	29, // 6: fetcher.Message.reactions:type_name -> fetcher.Message.ReactionsEntry
+	30, // 7: fetcher.Message.edited_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: fetcher.Message.change_type:type_name -> fetcher.ChangeType
+	7,  // 9: fetcher.Message.entities:type_name -> fetcher.TextEntity
+	10, // 10: fetcher.Message.duplicate_of:type_name -> fetcher.MessageRef
+	3,  // 11: fetcher.ChannelStatus.error_code:type_name -> fetcher.FetchErrorCode
+	9,  // 12: fetcher.FetchResponse.messages:type_name -> fetcher.Message
+	11, // 13: fetcher.FetchResponse.channels:type_name -> fetcher.ChannelStatus
+	9,  // 14: fetcher.MessageChunk.messages:type_name -> fetcher.Message
+	11, // 15: fetcher.FetchProgress.status:type_name -> fetcher.ChannelStatus
+	13, // 16: fetcher.FetchStreamResponse.chunk:type_name -> fetcher.MessageChunk
+	14, // 17: fetcher.FetchStreamResponse.progress:type_name -> fetcher.FetchProgress
+	30, // 18: fetcher.BatchNotification.min_ts:type_name -> google.protobuf.Timestamp
+	30, // 19: fetcher.BatchNotification.max_ts:type_name -> google.protobuf.Timestamp
+	30, // 20: fetcher.BatchNotification.created_at:type_name -> google.protobuf.Timestamp
+	4,  // 21: fetcher.FetchJob.status:type_name -> fetcher.JobStatus
+	30, // 22: fetcher.FetchJob.left_bound:type_name -> google.protobuf.Timestamp
+	30, // 23: fetcher.FetchJob.right_bound:type_name -> google.protobuf.Timestamp
+	11, // 24: fetcher.FetchJob.channels:type_name -> fetcher.ChannelStatus
+	30, // 25: fetcher.FetchJob.created_at:type_name -> google.protobuf.Timestamp
+	30, // 26: fetcher.FetchJob.updated_at:type_name -> google.protobuf.Timestamp
+	17, // 27: fetcher.StartFetchJobResponse.job:type_name -> fetcher.FetchJob
+	17, // 28: fetcher.GetFetchJobResponse.job:type_name -> fetcher.FetchJob
+	17, // 29: fetcher.CancelFetchJobResponse.job:type_name -> fetcher.FetchJob
+	30, // 30: fetcher.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	24, // 31: fetcher.UnsubscribeChatFolderResponse.subscription:type_name -> fetcher.Subscription
+	24, // 32: fetcher.ListSubscriptionsResponse.subscriptions:type_name -> fetcher.Subscription
+	6,  // 33: fetcher.FetcherService.Fetch:input_type -> fetcher.FetchRequest
+	6,  // 34: fetcher.FetcherService.FetchStream:input_type -> fetcher.FetchRequest
+	6,  // 35: fetcher.FetcherService.StartFetchJob:input_type -> fetcher.FetchRequest
+	19, // 36: fetcher.FetcherService.GetFetchJob:input_type -> fetcher.GetFetchJobRequest
+	21, // 37: fetcher.FetcherService.CancelFetchJob:input_type -> fetcher.CancelFetchJobRequest
+	23, // 38: fetcher.FetcherService.SubscribeChat:input_type -> fetcher.SubscribeChatFolderRequest
+	25, // 39: fetcher.FetcherService.UnsubscribeChatFolder:input_type -> fetcher.UnsubscribeChatFolderRequest
+	27, // 40: fetcher.FetcherService.ListSubscriptions:input_type -> fetcher.ListSubscriptionsRequest
+	12, // 41: fetcher.FetcherService.Fetch:output_type -> fetcher.FetchResponse
+	15, // 42: fetcher.FetcherService.FetchStream:output_type -> fetcher.FetchStreamResponse
+	18, // 43: fetcher.FetcherService.StartFetchJob:output_type -> fetcher.StartFetchJobResponse
+	20, // 44: fetcher.FetcherService.GetFetchJob:output_type -> fetcher.GetFetchJobResponse
+	22, // 45: fetcher.FetcherService.CancelFetchJob:output_type -> fetcher.CancelFetchJobResponse
+	5,  // 46: fetcher.FetcherService.SubscribeChat:output_type -> fetcher.Empty
+	26, // 47: fetcher.FetcherService.UnsubscribeChatFolder:output_type -> fetcher.UnsubscribeChatFolderResponse
+	28, // 48: fetcher.FetcherService.ListSubscriptions:output_type -> fetcher.ListSubscriptionsResponse
+	41, // [41:49] is the sub-list for method output_type
+	33, // [33:41] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
+}
+
+func init() { file_proto_fetcher_fetch_proto_init() }
 func file_proto_fetcher_fetch_proto_init() {
 	if File_proto_fetcher_fetch_proto != nil {
 		return
 	}
 	file_proto_fetcher_fetch_proto_msgTypes[1].OneofWrappers = []any{}
+	file_proto_fetcher_fetch_proto_msgTypes[2].OneofWrappers = []any{}
+	file_proto_fetcher_fetch_proto_msgTypes[3].OneofWrappers = []any{
+		(*Sender_UserId)(nil),
+		(*Sender_ChatId)(nil),
+	}
+	file_proto_fetcher_fetch_proto_msgTypes[4].OneofWrappers = []any{}
+	file_proto_fetcher_fetch_proto_msgTypes[6].OneofWrappers = []any{}
+	file_proto_fetcher_fetch_proto_msgTypes[10].OneofWrappers = []any{
+		(*FetchStreamResponse_Chunk)(nil),
+		(*FetchStreamResponse_Progress)(nil),
+	}
+	file_proto_fetcher_fetch_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_fetcher_fetch_proto_rawDesc), len(file_proto_fetcher_fetch_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      5,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_fetcher_fetch_proto_goTypes,
 		DependencyIndexes: file_proto_fetcher_fetch_proto_depIdxs,
+		EnumInfos:         file_proto_fetcher_fetch_proto_enumTypes,
 		MessageInfos:      file_proto_fetcher_fetch_proto_msgTypes,
 	}.Build()
 	File_proto_fetcher_fetch_proto = out.File
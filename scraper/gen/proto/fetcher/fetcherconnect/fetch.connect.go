@@ -35,15 +35,39 @@ const (
 const (
 	// FetcherServiceFetchProcedure is the fully-qualified name of the FetcherService's Fetch RPC.
 	FetcherServiceFetchProcedure = "/fetcher.FetcherService/Fetch"
+	// FetcherServiceFetchStreamProcedure is the fully-qualified name of the FetcherService's
+	// FetchStream RPC.
+	FetcherServiceFetchStreamProcedure = "/fetcher.FetcherService/FetchStream"
+	// FetcherServiceStartFetchJobProcedure is the fully-qualified name of the FetcherService's
+	// StartFetchJob RPC.
+	FetcherServiceStartFetchJobProcedure = "/fetcher.FetcherService/StartFetchJob"
+	// FetcherServiceGetFetchJobProcedure is the fully-qualified name of the FetcherService's
+	// GetFetchJob RPC.
+	FetcherServiceGetFetchJobProcedure = "/fetcher.FetcherService/GetFetchJob"
+	// FetcherServiceCancelFetchJobProcedure is the fully-qualified name of the FetcherService's
+	// CancelFetchJob RPC.
+	FetcherServiceCancelFetchJobProcedure = "/fetcher.FetcherService/CancelFetchJob"
 	// FetcherServiceSubscribeChatProcedure is the fully-qualified name of the FetcherService's
 	// SubscribeChat RPC.
 	FetcherServiceSubscribeChatProcedure = "/fetcher.FetcherService/SubscribeChat"
+	// FetcherServiceUnsubscribeChatFolderProcedure is the fully-qualified name of the FetcherService's
+	// UnsubscribeChatFolder RPC.
+	FetcherServiceUnsubscribeChatFolderProcedure = "/fetcher.FetcherService/UnsubscribeChatFolder"
+	// FetcherServiceListSubscriptionsProcedure is the fully-qualified name of the FetcherService's
+	// ListSubscriptions RPC.
+	FetcherServiceListSubscriptionsProcedure = "/fetcher.FetcherService/ListSubscriptions"
 )
 
 // FetcherServiceClient is a client for the fetcher.FetcherService service.
 type FetcherServiceClient interface {
 	Fetch(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.FetchResponse], error)
+	FetchStream(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.ServerStreamForClient[fetcher.FetchStreamResponse], error)
+	StartFetchJob(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.StartFetchJobResponse], error)
+	GetFetchJob(context.Context, *connect.Request[fetcher.GetFetchJobRequest]) (*connect.Response[fetcher.GetFetchJobResponse], error)
+	CancelFetchJob(context.Context, *connect.Request[fetcher.CancelFetchJobRequest]) (*connect.Response[fetcher.CancelFetchJobResponse], error)
 	SubscribeChat(context.Context, *connect.Request[fetcher.SubscribeChatFolderRequest]) (*connect.Response[fetcher.Empty], error)
+	UnsubscribeChatFolder(context.Context, *connect.Request[fetcher.UnsubscribeChatFolderRequest]) (*connect.Response[fetcher.UnsubscribeChatFolderResponse], error)
+	ListSubscriptions(context.Context, *connect.Request[fetcher.ListSubscriptionsRequest]) (*connect.Response[fetcher.ListSubscriptionsResponse], error)
 }
 
 // NewFetcherServiceClient constructs a client for the fetcher.FetcherService service. By default,
@@ -63,19 +87,61 @@ func NewFetcherServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(fetcherServiceMethods.ByName("Fetch")),
 			connect.WithClientOptions(opts...),
 		),
+		fetchStream: connect.NewClient[fetcher.FetchRequest, fetcher.FetchStreamResponse](
+			httpClient,
+			baseURL+FetcherServiceFetchStreamProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("FetchStream")),
+			connect.WithClientOptions(opts...),
+		),
+		startFetchJob: connect.NewClient[fetcher.FetchRequest, fetcher.StartFetchJobResponse](
+			httpClient,
+			baseURL+FetcherServiceStartFetchJobProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("StartFetchJob")),
+			connect.WithClientOptions(opts...),
+		),
+		getFetchJob: connect.NewClient[fetcher.GetFetchJobRequest, fetcher.GetFetchJobResponse](
+			httpClient,
+			baseURL+FetcherServiceGetFetchJobProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("GetFetchJob")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelFetchJob: connect.NewClient[fetcher.CancelFetchJobRequest, fetcher.CancelFetchJobResponse](
+			httpClient,
+			baseURL+FetcherServiceCancelFetchJobProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("CancelFetchJob")),
+			connect.WithClientOptions(opts...),
+		),
 		subscribeChat: connect.NewClient[fetcher.SubscribeChatFolderRequest, fetcher.Empty](
 			httpClient,
 			baseURL+FetcherServiceSubscribeChatProcedure,
 			connect.WithSchema(fetcherServiceMethods.ByName("SubscribeChat")),
 			connect.WithClientOptions(opts...),
 		),
+		unsubscribeChatFolder: connect.NewClient[fetcher.UnsubscribeChatFolderRequest, fetcher.UnsubscribeChatFolderResponse](
+			httpClient,
+			baseURL+FetcherServiceUnsubscribeChatFolderProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("UnsubscribeChatFolder")),
+			connect.WithClientOptions(opts...),
+		),
+		listSubscriptions: connect.NewClient[fetcher.ListSubscriptionsRequest, fetcher.ListSubscriptionsResponse](
+			httpClient,
+			baseURL+FetcherServiceListSubscriptionsProcedure,
+			connect.WithSchema(fetcherServiceMethods.ByName("ListSubscriptions")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // fetcherServiceClient implements FetcherServiceClient.
 type fetcherServiceClient struct {
-	fetch         *connect.Client[fetcher.FetchRequest, fetcher.FetchResponse]
-	subscribeChat *connect.Client[fetcher.SubscribeChatFolderRequest, fetcher.Empty]
+	fetch                 *connect.Client[fetcher.FetchRequest, fetcher.FetchResponse]
+	fetchStream           *connect.Client[fetcher.FetchRequest, fetcher.FetchStreamResponse]
+	startFetchJob         *connect.Client[fetcher.FetchRequest, fetcher.StartFetchJobResponse]
+	getFetchJob           *connect.Client[fetcher.GetFetchJobRequest, fetcher.GetFetchJobResponse]
+	cancelFetchJob        *connect.Client[fetcher.CancelFetchJobRequest, fetcher.CancelFetchJobResponse]
+	subscribeChat         *connect.Client[fetcher.SubscribeChatFolderRequest, fetcher.Empty]
+	unsubscribeChatFolder *connect.Client[fetcher.UnsubscribeChatFolderRequest, fetcher.UnsubscribeChatFolderResponse]
+	listSubscriptions     *connect.Client[fetcher.ListSubscriptionsRequest, fetcher.ListSubscriptionsResponse]
 }
 
 // Fetch calls fetcher.FetcherService.Fetch.
@@ -83,15 +149,51 @@ func (c *fetcherServiceClient) Fetch(ctx context.Context, req *connect.Request[f
 	return c.fetch.CallUnary(ctx, req)
 }
 
+// FetchStream calls fetcher.FetcherService.FetchStream.
+func (c *fetcherServiceClient) FetchStream(ctx context.Context, req *connect.Request[fetcher.FetchRequest]) (*connect.ServerStreamForClient[fetcher.FetchStreamResponse], error) {
+	return c.fetchStream.CallServerStream(ctx, req)
+}
+
+// StartFetchJob calls fetcher.FetcherService.StartFetchJob.
+func (c *fetcherServiceClient) StartFetchJob(ctx context.Context, req *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.StartFetchJobResponse], error) {
+	return c.startFetchJob.CallUnary(ctx, req)
+}
+
+// GetFetchJob calls fetcher.FetcherService.GetFetchJob.
+func (c *fetcherServiceClient) GetFetchJob(ctx context.Context, req *connect.Request[fetcher.GetFetchJobRequest]) (*connect.Response[fetcher.GetFetchJobResponse], error) {
+	return c.getFetchJob.CallUnary(ctx, req)
+}
+
+// CancelFetchJob calls fetcher.FetcherService.CancelFetchJob.
+func (c *fetcherServiceClient) CancelFetchJob(ctx context.Context, req *connect.Request[fetcher.CancelFetchJobRequest]) (*connect.Response[fetcher.CancelFetchJobResponse], error) {
+	return c.cancelFetchJob.CallUnary(ctx, req)
+}
+
 // SubscribeChat calls fetcher.FetcherService.SubscribeChat.
 func (c *fetcherServiceClient) SubscribeChat(ctx context.Context, req *connect.Request[fetcher.SubscribeChatFolderRequest]) (*connect.Response[fetcher.Empty], error) {
 	return c.subscribeChat.CallUnary(ctx, req)
 }
 
+// UnsubscribeChatFolder calls fetcher.FetcherService.UnsubscribeChatFolder.
+func (c *fetcherServiceClient) UnsubscribeChatFolder(ctx context.Context, req *connect.Request[fetcher.UnsubscribeChatFolderRequest]) (*connect.Response[fetcher.UnsubscribeChatFolderResponse], error) {
+	return c.unsubscribeChatFolder.CallUnary(ctx, req)
+}
+
+// ListSubscriptions calls fetcher.FetcherService.ListSubscriptions.
+func (c *fetcherServiceClient) ListSubscriptions(ctx context.Context, req *connect.Request[fetcher.ListSubscriptionsRequest]) (*connect.Response[fetcher.ListSubscriptionsResponse], error) {
+	return c.listSubscriptions.CallUnary(ctx, req)
+}
+
 // FetcherServiceHandler is an implementation of the fetcher.FetcherService service.
 type FetcherServiceHandler interface {
 	Fetch(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.FetchResponse], error)
+	FetchStream(context.Context, *connect.Request[fetcher.FetchRequest], *connect.ServerStream[fetcher.FetchStreamResponse]) error
+	StartFetchJob(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.StartFetchJobResponse], error)
+	GetFetchJob(context.Context, *connect.Request[fetcher.GetFetchJobRequest]) (*connect.Response[fetcher.GetFetchJobResponse], error)
+	CancelFetchJob(context.Context, *connect.Request[fetcher.CancelFetchJobRequest]) (*connect.Response[fetcher.CancelFetchJobResponse], error)
 	SubscribeChat(context.Context, *connect.Request[fetcher.SubscribeChatFolderRequest]) (*connect.Response[fetcher.Empty], error)
+	UnsubscribeChatFolder(context.Context, *connect.Request[fetcher.UnsubscribeChatFolderRequest]) (*connect.Response[fetcher.UnsubscribeChatFolderResponse], error)
+	ListSubscriptions(context.Context, *connect.Request[fetcher.ListSubscriptionsRequest]) (*connect.Response[fetcher.ListSubscriptionsResponse], error)
 }
 
 // NewFetcherServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -107,18 +209,66 @@ func NewFetcherServiceHandler(svc FetcherServiceHandler, opts ...connect.Handler
 		connect.WithSchema(fetcherServiceMethods.ByName("Fetch")),
 		connect.WithHandlerOptions(opts...),
 	)
+	fetcherServiceFetchStreamHandler := connect.NewServerStreamHandler(
+		FetcherServiceFetchStreamProcedure,
+		svc.FetchStream,
+		connect.WithSchema(fetcherServiceMethods.ByName("FetchStream")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fetcherServiceStartFetchJobHandler := connect.NewUnaryHandler(
+		FetcherServiceStartFetchJobProcedure,
+		svc.StartFetchJob,
+		connect.WithSchema(fetcherServiceMethods.ByName("StartFetchJob")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fetcherServiceGetFetchJobHandler := connect.NewUnaryHandler(
+		FetcherServiceGetFetchJobProcedure,
+		svc.GetFetchJob,
+		connect.WithSchema(fetcherServiceMethods.ByName("GetFetchJob")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fetcherServiceCancelFetchJobHandler := connect.NewUnaryHandler(
+		FetcherServiceCancelFetchJobProcedure,
+		svc.CancelFetchJob,
+		connect.WithSchema(fetcherServiceMethods.ByName("CancelFetchJob")),
+		connect.WithHandlerOptions(opts...),
+	)
 	fetcherServiceSubscribeChatHandler := connect.NewUnaryHandler(
 		FetcherServiceSubscribeChatProcedure,
 		svc.SubscribeChat,
 		connect.WithSchema(fetcherServiceMethods.ByName("SubscribeChat")),
 		connect.WithHandlerOptions(opts...),
 	)
+	fetcherServiceUnsubscribeChatFolderHandler := connect.NewUnaryHandler(
+		FetcherServiceUnsubscribeChatFolderProcedure,
+		svc.UnsubscribeChatFolder,
+		connect.WithSchema(fetcherServiceMethods.ByName("UnsubscribeChatFolder")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fetcherServiceListSubscriptionsHandler := connect.NewUnaryHandler(
+		FetcherServiceListSubscriptionsProcedure,
+		svc.ListSubscriptions,
+		connect.WithSchema(fetcherServiceMethods.ByName("ListSubscriptions")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/fetcher.FetcherService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case FetcherServiceFetchProcedure:
 			fetcherServiceFetchHandler.ServeHTTP(w, r)
+		case FetcherServiceFetchStreamProcedure:
+			fetcherServiceFetchStreamHandler.ServeHTTP(w, r)
+		case FetcherServiceStartFetchJobProcedure:
+			fetcherServiceStartFetchJobHandler.ServeHTTP(w, r)
+		case FetcherServiceGetFetchJobProcedure:
+			fetcherServiceGetFetchJobHandler.ServeHTTP(w, r)
+		case FetcherServiceCancelFetchJobProcedure:
+			fetcherServiceCancelFetchJobHandler.ServeHTTP(w, r)
 		case FetcherServiceSubscribeChatProcedure:
 			fetcherServiceSubscribeChatHandler.ServeHTTP(w, r)
+		case FetcherServiceUnsubscribeChatFolderProcedure:
+			fetcherServiceUnsubscribeChatFolderHandler.ServeHTTP(w, r)
+		case FetcherServiceListSubscriptionsProcedure:
+			fetcherServiceListSubscriptionsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -132,6 +282,30 @@ func (UnimplementedFetcherServiceHandler) Fetch(context.Context, *connect.Reques
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.Fetch is not implemented"))
 }
 
+func (UnimplementedFetcherServiceHandler) FetchStream(context.Context, *connect.Request[fetcher.FetchRequest], *connect.ServerStream[fetcher.FetchStreamResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.FetchStream is not implemented"))
+}
+
+func (UnimplementedFetcherServiceHandler) StartFetchJob(context.Context, *connect.Request[fetcher.FetchRequest]) (*connect.Response[fetcher.StartFetchJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.StartFetchJob is not implemented"))
+}
+
+func (UnimplementedFetcherServiceHandler) GetFetchJob(context.Context, *connect.Request[fetcher.GetFetchJobRequest]) (*connect.Response[fetcher.GetFetchJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.GetFetchJob is not implemented"))
+}
+
+func (UnimplementedFetcherServiceHandler) CancelFetchJob(context.Context, *connect.Request[fetcher.CancelFetchJobRequest]) (*connect.Response[fetcher.CancelFetchJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.CancelFetchJob is not implemented"))
+}
+
 func (UnimplementedFetcherServiceHandler) SubscribeChat(context.Context, *connect.Request[fetcher.SubscribeChatFolderRequest]) (*connect.Response[fetcher.Empty], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.SubscribeChat is not implemented"))
 }
+
+func (UnimplementedFetcherServiceHandler) UnsubscribeChatFolder(context.Context, *connect.Request[fetcher.UnsubscribeChatFolderRequest]) (*connect.Response[fetcher.UnsubscribeChatFolderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.UnsubscribeChatFolder is not implemented"))
+}
+
+func (UnimplementedFetcherServiceHandler) ListSubscriptions(context.Context, *connect.Request[fetcher.ListSubscriptionsRequest]) (*connect.Response[fetcher.ListSubscriptionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("fetcher.FetcherService.ListSubscriptions is not implemented"))
+}
@@ -0,0 +1,83 @@
+package chats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+type Info struct {
+	Id       int64
+	Username string
+	Title    string
+	Type     client.ChatType
+
+	expiresAt time.Time
+}
+
+type ResolveFunc func(ctx context.Context, chatId int64) (*Info, error)
+
+// Cache keeps chat metadata for ttl, so that scraping doesn't request the
+// same chat from TDLib for every page and every incoming message.
+type Cache struct {
+	resolve ResolveFunc
+	ttl     time.Duration
+
+	mu          sync.RWMutex
+	chats       map[int64]*Info
+	supergroups map[int64]int64
+}
+
+func NewCache(ttl time.Duration, resolve ResolveFunc) *Cache {
+	return &Cache{
+		resolve:     resolve,
+		ttl:         ttl,
+		chats:       make(map[int64]*Info),
+		supergroups: make(map[int64]int64),
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, chatId int64) (*Info, error) {
+	c.mu.RLock()
+	info, ok := c.chats[chatId]
+	c.mu.RUnlock()
+
+	if ok && time.Now().Before(info.expiresAt) {
+		return info, nil
+	}
+
+	info, err := c.resolve(ctx, chatId)
+	if err != nil {
+		return nil, err
+	}
+	info.expiresAt = time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chats[chatId] = info
+	if group, ok := info.Type.(*client.ChatTypeSupergroup); ok {
+		c.supergroups[group.SupergroupId] = chatId
+	}
+
+	return info, nil
+}
+
+func (c *Cache) Invalidate(chatId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.chats, chatId)
+}
+
+func (c *Cache) InvalidateSupergroup(supergroupId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if chatId, ok := c.supergroups[supergroupId]; ok {
+		delete(c.chats, chatId)
+		delete(c.supergroups, supergroupId)
+	}
+}
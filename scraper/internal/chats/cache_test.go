@@ -0,0 +1,100 @@
+package chats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+// resolver counts calls, so that tests can tell cache hits from misses.
+type resolver struct {
+	calls map[int64]int
+	err   error
+}
+
+func (r *resolver) resolve(ctx context.Context, chatId int64) (*Info, error) {
+	r.calls[chatId]++
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &Info{
+		Id:   chatId,
+		Type: &client.ChatTypeSupergroup{SupergroupId: -chatId},
+	}, nil
+}
+
+func TestCacheGet(t *testing.T) {
+	ctx := context.Background()
+	r := &resolver{calls: map[int64]int{}}
+	cache := NewCache(time.Hour, r.resolve)
+
+	for range 3 {
+		info, err := cache.Get(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Id != 1 {
+			t.Errorf("Get() = %+v, want chat 1", info)
+		}
+	}
+	if r.calls[1] != 1 {
+		t.Errorf("chat is resolved %d times, want 1", r.calls[1])
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	ctx := context.Background()
+	r := &resolver{calls: map[int64]int{}}
+	cache := NewCache(0, r.resolve)
+
+	for range 2 {
+		if _, err := cache.Get(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.calls[1] != 2 {
+		t.Errorf("chat is resolved %d times, want 2", r.calls[1])
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	r := &resolver{calls: map[int64]int{}}
+	cache := NewCache(time.Hour, r.resolve)
+
+	for _, id := range []int64{1, 2} {
+		if _, err := cache.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache.Invalidate(1)
+	cache.InvalidateSupergroup(-2)
+	cache.InvalidateSupergroup(-3)
+
+	for _, id := range []int64{1, 2} {
+		if _, err := cache.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if r.calls[id] != 2 {
+			t.Errorf("chat %d is resolved %d times, want 2", id, r.calls[id])
+		}
+	}
+}
+
+func TestCacheError(t *testing.T) {
+	failure := errors.New("unavailable")
+	r := &resolver{calls: map[int64]int{}, err: failure}
+	cache := NewCache(time.Hour, r.resolve)
+
+	if _, err := cache.Get(context.Background(), 1); !errors.Is(err, failure) {
+		t.Errorf("Get() error = %v, want %v", err, failure)
+	}
+	if _, err := cache.Get(context.Background(), 1); err == nil {
+		t.Error("failed lookup is cached")
+	}
+}
@@ -0,0 +1,212 @@
+package dedup
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+type Mode string
+
+const (
+	ModeDrop Mode = "drop"
+	ModeTag  Mode = "tag"
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeDrop, ModeTag:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown near-duplicate mode %q", s)
+	}
+}
+
+type fingerprint struct {
+	hash      uint64
+	chatId    int64
+	messageId int64
+	ts        time.Time
+}
+
+// band identifies bits of fingerprint hash that fall into the given band.
+type band struct {
+	index int
+	value uint64
+}
+
+// fingerprintHeap orders fingerprints by message timestamp, so that the ones
+// outside of the window are removed first.
+type fingerprintHeap []*fingerprint
+
+func (h fingerprintHeap) Len() int           { return len(h) }
+func (h fingerprintHeap) Less(i, j int) bool { return h[i].ts.Before(h[j].ts) }
+func (h fingerprintHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *fingerprintHeap) Push(x any) {
+	*h = append(*h, x.(*fingerprint))
+}
+
+func (h *fingerprintHeap) Pop() any {
+	old := *h
+	fp := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return fp
+}
+
+// NearDuplicates detects messages whose text is close to a message posted
+// within the window, e.g. reposts with small edits. Duplicates are either
+// dropped or tagged with the message they repeat, depending on mode.
+//
+// Hashes are split into threshold+1 bands, so any two hashes within the
+// threshold share at least one band, and only fingerprints indexed under a
+// band of the message are compared. Fingerprints are kept in memory, so the
+// window starts over after restart.
+type NearDuplicates struct {
+	window    time.Duration
+	threshold int
+	mode      Mode
+	widths    []int
+
+	mu      sync.Mutex
+	latest  time.Time
+	byTs    fingerprintHeap
+	buckets map[band][]*fingerprint
+}
+
+func NewNearDuplicates(window time.Duration, threshold int, mode Mode) *NearDuplicates {
+	bands := min(max(threshold+1, 1), 64)
+	widths := make([]int, bands)
+	for i := range widths {
+		widths[i] = 64 / bands
+		if i < 64%bands {
+			widths[i]++
+		}
+	}
+
+	return &NearDuplicates{
+		window:    window,
+		threshold: threshold,
+		mode:      mode,
+		widths:    widths,
+		buckets:   make(map[band][]*fingerprint),
+	}
+}
+
+func (d *NearDuplicates) Process(msgs []*pb.Message) []*pb.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.expire()
+
+	out := make([]*pb.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if !tracked(msg) || msg.Text == "" {
+			out = append(out, msg)
+			continue
+		}
+
+		fp := &fingerprint{
+			hash:      SimHash(msg.Text),
+			chatId:    msg.ChatId,
+			messageId: msg.MessageId,
+			ts:        time.Now(),
+		}
+		if msg.Ts != nil {
+			fp.ts = msg.Ts.AsTime()
+		}
+
+		canonical := d.find(fp)
+		if canonical == nil {
+			d.add(fp)
+			out = append(out, msg)
+			continue
+		}
+
+		zap.L().Debug(
+			"Found near-duplicate message",
+			zap.Int64("chat_id", msg.ChatId),
+			zap.Int64("message_id", msg.MessageId),
+			zap.Int64("canonical_chat_id", canonical.chatId),
+			zap.Int64("canonical_message_id", canonical.messageId),
+		)
+
+		if d.mode == ModeDrop {
+			continue
+		}
+
+		// Message may still be referenced by RPC response being serialized,
+		// so copy is tagged instead
+		tagged := proto.Clone(msg).(*pb.Message)
+		tagged.DuplicateOf = &pb.MessageRef{
+			ChatId:    canonical.chatId,
+			MessageId: canonical.messageId,
+		}
+		out = append(out, tagged)
+	}
+
+	return out
+}
+
+func (d *NearDuplicates) bands(hash uint64) []band {
+	bands := make([]band, len(d.widths))
+	shift := 0
+	for i, width := range d.widths {
+		bands[i] = band{index: i, value: (hash >> shift) & (1<<width - 1)}
+		shift += width
+	}
+	return bands
+}
+
+func (d *NearDuplicates) find(fp *fingerprint) *fingerprint {
+	for _, b := range d.bands(fp.hash) {
+		for _, candidate := range d.buckets[b] {
+			if candidate.chatId == fp.chatId && candidate.messageId == fp.messageId {
+				continue
+			}
+			if fp.ts.Sub(candidate.ts).Abs() > d.window {
+				continue
+			}
+			if Distance(candidate.hash, fp.hash) <= d.threshold {
+				return candidate
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *NearDuplicates) add(fp *fingerprint) {
+	heap.Push(&d.byTs, fp)
+	for _, b := range d.bands(fp.hash) {
+		d.buckets[b] = append(d.buckets[b], fp)
+	}
+	if fp.ts.After(d.latest) {
+		d.latest = fp.ts
+	}
+}
+
+// expire removes fingerprints of messages posted more than window before
+// the latest one.
+func (d *NearDuplicates) expire() {
+	cutoff := d.latest.Add(-d.window)
+	for d.byTs.Len() > 0 && d.byTs[0].ts.Before(cutoff) {
+		fp := heap.Pop(&d.byTs).(*fingerprint)
+		for _, b := range d.bands(fp.hash) {
+			bucket := slices.DeleteFunc(d.buckets[b], func(other *fingerprint) bool {
+				return other == fp
+			})
+			if len(bucket) == 0 {
+				delete(d.buckets, b)
+			} else {
+				d.buckets[b] = bucket
+			}
+		}
+	}
+}
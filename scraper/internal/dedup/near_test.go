@@ -0,0 +1,186 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	original = "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года"
+	repost   = "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года. Подписывайтесь"
+	other    = "Погода в Москве завтра будет солнечной, без осадков, температура до двадцати градусов тепла"
+)
+
+func newMessage(chatId, messageId int64, text string) *pb.Message {
+	return &pb.Message{
+		ChatId:     chatId,
+		MessageId:  messageId,
+		Text:       text,
+		ChangeType: pb.ChangeType_CHANGE_TYPE_NEW,
+	}
+}
+
+func TestNearDuplicatesTag(t *testing.T) {
+	d := NewNearDuplicates(time.Hour, threshold, ModeTag)
+
+	first := d.Process([]*pb.Message{newMessage(1, 10, original)})
+	dup := newMessage(2, 20, repost)
+	out := d.Process([]*pb.Message{dup, newMessage(3, 30, other)})
+
+	if len(first) != 1 || first[0].DuplicateOf != nil {
+		t.Fatalf("original is tagged: %v", first)
+	}
+	if len(out) != 2 {
+		t.Fatalf("got %d messages, want 2", len(out))
+	}
+
+	ref := out[0].DuplicateOf
+	if ref == nil || ref.ChatId != 1 || ref.MessageId != 10 {
+		t.Errorf("duplicate_of = %v, want 1/10", ref)
+	}
+	if dup.DuplicateOf != nil {
+		t.Error("input message is modified")
+	}
+	if out[1].DuplicateOf != nil {
+		t.Error("unrelated message is tagged")
+	}
+}
+
+func TestNearDuplicatesDrop(t *testing.T) {
+	d := NewNearDuplicates(time.Hour, threshold, ModeDrop)
+
+	out := d.Process([]*pb.Message{
+		newMessage(1, 10, original),
+		newMessage(2, 20, repost),
+		newMessage(3, 30, other),
+	})
+
+	if len(out) != 2 || out[0].MessageId != 10 || out[1].MessageId != 30 {
+		t.Errorf("got %v, want messages 10 and 30", out)
+	}
+}
+
+func TestNearDuplicatesSkipped(t *testing.T) {
+	d := NewNearDuplicates(time.Hour, threshold, ModeDrop)
+
+	edited := newMessage(1, 10, original)
+	edited.ChangeType = pb.ChangeType_CHANGE_TYPE_EDITED
+
+	out := d.Process([]*pb.Message{
+		newMessage(1, 10, original),
+		// Same message is never a duplicate of itself
+		newMessage(1, 10, original),
+		edited,
+		newMessage(2, 20, ""),
+		newMessage(3, 30, ""),
+	})
+
+	if len(out) != 5 {
+		t.Errorf("got %d messages, want 5", len(out))
+	}
+}
+
+func TestNearDuplicatesWindow(t *testing.T) {
+	posted := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(msg *pb.Message, ts time.Time) *pb.Message {
+		msg.Ts = timestamppb.New(ts)
+		return msg
+	}
+
+	tests := []struct {
+		name      string
+		ts        time.Time
+		duplicate bool
+	}{
+		{"later within window", posted.Add(30 * time.Minute), true},
+		{"earlier within window", posted.Add(-30 * time.Minute), true},
+		{"later outside window", posted.Add(2 * time.Hour), false},
+		{"earlier outside window", posted.Add(-2 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewNearDuplicates(time.Hour, threshold, ModeDrop)
+
+			d.Process([]*pb.Message{at(newMessage(1, 10, original), posted)})
+			out := d.Process([]*pb.Message{at(newMessage(2, 20, repost), tt.ts)})
+
+			if got := len(out) == 0; got != tt.duplicate {
+				t.Errorf("duplicate = %v, want %v", got, tt.duplicate)
+			}
+		})
+	}
+}
+
+func TestNearDuplicatesExpire(t *testing.T) {
+	d := NewNearDuplicates(time.Hour, threshold, ModeDrop)
+	posted := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	first := newMessage(1, 10, original)
+	first.Ts = timestamppb.New(posted)
+	latest := newMessage(3, 30, other)
+	latest.Ts = timestamppb.New(posted.Add(3 * time.Hour))
+
+	d.Process([]*pb.Message{first, latest})
+	d.Process(nil)
+
+	if d.byTs.Len() != 1 || d.byTs[0].messageId != 30 {
+		t.Errorf("fingerprints = %v, want only the latest one", d.byTs)
+	}
+	for _, b := range d.bands(SimHash(original)) {
+		if _, ok := d.buckets[b]; ok {
+			t.Errorf("band %v of expired fingerprint is still indexed", b)
+		}
+	}
+}
+
+func TestNearDuplicatesBands(t *testing.T) {
+	d := NewNearDuplicates(time.Hour, threshold, ModeDrop)
+	ts := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	const hash = 0x0123456789abcdef
+	d.add(&fingerprint{hash: hash, chatId: 1, messageId: 10, ts: ts})
+
+	// One bit is flipped in each of threshold bands, so that only the last
+	// band matches exactly
+	var spread uint64
+	shift := 0
+	for _, width := range d.widths[:threshold] {
+		spread |= 1 << shift
+		shift += width
+	}
+
+	tests := []struct {
+		name  string
+		hash  uint64
+		found bool
+	}{
+		{"same", hash, true},
+		{"spread over bands", hash ^ spread, true},
+		{"over threshold", hash ^ spread ^ 1<<63, false},
+		{"all bits", ^uint64(hash), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &fingerprint{hash: tt.hash, chatId: 2, messageId: 20, ts: ts}
+			if got := d.find(fp) != nil; got != tt.found {
+				t.Errorf("found = %v, want %v", got, tt.found)
+			}
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, s := range []string{"drop", "tag"} {
+		if _, err := ParseMode(s); err != nil {
+			t.Errorf("ParseMode(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseMode("ignore"); err == nil {
+		t.Error("ParseMode accepted unknown mode")
+	}
+}
@@ -0,0 +1,83 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/redis/go-redis/v9"
+)
+
+// Seen is a set of already written messages keyed by (chat id, message id).
+// Every entry is a separate Redis key with TTL, so that the set stays bounded.
+type Seen struct {
+	rdb    *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+func NewSeen(rdb *redis.Client, prefix string, ttl time.Duration) *Seen {
+	return &Seen{
+		rdb:    rdb,
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+func (s *Seen) key(msg *pb.Message) string {
+	return fmt.Sprintf("%s:%d:%d", s.prefix, msg.ChatId, msg.MessageId)
+}
+
+// Edits and deletions refer to already written messages and
+// must reach consumers, so only new messages are deduplicated
+func tracked(msg *pb.Message) bool {
+	return msg.ChangeType == pb.ChangeType_CHANGE_TYPE_NEW
+}
+
+// Filter drops messages that were already written or occur in msgs more than
+// once. Remaining messages are claimed at once, so that concurrent writers
+// don't both pass the same message. Claims of messages that failed to be
+// written must be dropped with Release.
+func (s *Seen) Filter(ctx context.Context, msgs []*pb.Message) ([]*pb.Message, error) {
+	pipe := s.rdb.Pipeline()
+	claims := make([]*redis.BoolCmd, len(msgs))
+	for i, msg := range msgs {
+		if tracked(msg) {
+			claims[i] = pipe.SetNX(ctx, s.key(msg), 1, s.ttl)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to claim seen messages: %w", err)
+	}
+
+	filtered := make([]*pb.Message, 0, len(msgs))
+	for i, msg := range msgs {
+		if claims[i] != nil && !claims[i].Val() {
+			continue
+		}
+		filtered = append(filtered, msg)
+	}
+
+	return filtered, nil
+}
+
+// Release removes claims of messages, so that they are written again next
+// time they're received.
+func (s *Seen) Release(ctx context.Context, msgs []*pb.Message) error {
+	keys := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		if tracked(msg) {
+			keys = append(keys, s.key(msg))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := s.rdb.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to release seen messages: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package dedup
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestSeen(t *testing.T) (*Seen, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewSeen(rdb, "test:seen", time.Hour), mr
+}
+
+func message(chatId int64, messageId int64, change pb.ChangeType) *pb.Message {
+	return &pb.Message{ChatId: chatId, MessageId: messageId, ChangeType: change}
+}
+
+func ids(msgs []*pb.Message) [][2]int64 {
+	result := make([][2]int64, len(msgs))
+	for i, msg := range msgs {
+		result[i] = [2]int64{msg.ChatId, msg.MessageId}
+	}
+	return result
+}
+
+func TestSeenFilter(t *testing.T) {
+	ctx := context.Background()
+	seen, _ := newTestSeen(t)
+
+	_, err := seen.Filter(ctx, []*pb.Message{message(1, 1, pb.ChangeType_CHANGE_TYPE_NEW)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filtered, err := seen.Filter(ctx, []*pb.Message{
+		message(1, 1, pb.ChangeType_CHANGE_TYPE_NEW),
+		message(1, 2, pb.ChangeType_CHANGE_TYPE_NEW),
+		message(1, 2, pb.ChangeType_CHANGE_TYPE_NEW),
+		message(2, 1, pb.ChangeType_CHANGE_TYPE_NEW),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]int64{{1, 2}, {2, 1}}
+	got := ids(filtered)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+}
+
+func TestSeenConcurrentFilter(t *testing.T) {
+	ctx := context.Background()
+	seen, _ := newTestSeen(t)
+
+	const writers = 8
+	passed := make([]int, writers)
+
+	wg := sync.WaitGroup{}
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			filtered, err := seen.Filter(ctx, []*pb.Message{
+				message(1, 1, pb.ChangeType_CHANGE_TYPE_NEW),
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			passed[i] = len(filtered)
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range passed {
+		total += n
+	}
+	if total != 1 {
+		t.Errorf("message passed Filter() %d times, want once", total)
+	}
+}
+
+func TestSeenRelease(t *testing.T) {
+	ctx := context.Background()
+	seen, _ := newTestSeen(t)
+
+	msgs := []*pb.Message{message(1, 1, pb.ChangeType_CHANGE_TYPE_NEW)}
+	if _, err := seen.Filter(ctx, msgs); err != nil {
+		t.Fatal(err)
+	}
+	if err := seen.Release(ctx, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	filtered, err := seen.Filter(ctx, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 {
+		t.Errorf("Filter() = %v, want released message to pass again", ids(filtered))
+	}
+}
+
+func TestSeenKeepsChanges(t *testing.T) {
+	ctx := context.Background()
+	seen, mr := newTestSeen(t)
+
+	changes := []*pb.Message{
+		message(1, 1, pb.ChangeType_CHANGE_TYPE_EDITED),
+		message(1, 1, pb.ChangeType_CHANGE_TYPE_DELETED),
+	}
+	for range 2 {
+		filtered, err := seen.Filter(ctx, changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(filtered) != len(changes) {
+			t.Errorf("Filter() kept %d of %d edits and deletions", len(filtered), len(changes))
+		}
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("claimed keys = %v, want none for edits and deletions", keys)
+	}
+}
+
+func TestSeenExpires(t *testing.T) {
+	ctx := context.Background()
+	seen, mr := newTestSeen(t)
+
+	msgs := []*pb.Message{message(1, 1, pb.ChangeType_CHANGE_TYPE_NEW)}
+	if _, err := seen.Filter(ctx, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.FastForward(2 * time.Hour)
+
+	filtered, err := seen.Filter(ctx, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 {
+		t.Errorf("Filter() = %v, want message to be written again after TTL", ids(filtered))
+	}
+}
@@ -0,0 +1,88 @@
+package dedup
+
+import "testing"
+
+// threshold matches default of STREAMING_NEAR_DUP_THRESHOLD
+const threshold = 8
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0b1010, 0b0101, 4},
+		{0, ^uint64(0), 64},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%b, %b) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimHashEmpty(t *testing.T) {
+	if got := SimHash("  ...  "); got != 0 {
+		t.Errorf("SimHash of text without words = %x, want 0", got)
+	}
+}
+
+func TestSimHashDistance(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		duplicate bool
+	}{
+		{
+			name:      "identical",
+			duplicate: true,
+			a:         "Центробанк сохранил ключевую ставку на уровне 16 процентов годовых",
+			b:         "Центробанк сохранил ключевую ставку на уровне 16 процентов годовых",
+		},
+		{
+			name:      "case and punctuation",
+			duplicate: true,
+			a:         "Центробанк сохранил ключевую ставку на уровне 16 процентов годовых.",
+			b:         "ЦЕНТРОБАНК сохранил ключевую ставку — на уровне 16 процентов годовых!!!",
+		},
+		{
+			name:      "repost with appended source",
+			duplicate: true,
+			a:         "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года, следует из опубликованного доклада ведомства",
+			b:         "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года, следует из опубликованного доклада ведомства. Подписывайтесь на наш канал",
+		},
+		{
+			name:      "repost with changed word",
+			duplicate: true,
+			a:         "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года, следует из опубликованного доклада ведомства",
+			b:         "Министерство финансов заявило о росте ВВП на 3 процента в третьем квартале этого года по сравнению с аналогичным периодом прошлого года, следует из опубликованного доклада ведомства",
+		},
+		{
+			name: "unrelated",
+			a:    "Министерство финансов сообщило о росте ВВП на 3 процента в третьем квартале этого года",
+			b:    "Погода в Москве завтра будет солнечной, без осадков, температура до двадцати градусов",
+		},
+		{
+			name: "same topic, different text",
+			a:    "Центробанк сохранил ключевую ставку на уровне 16 процентов годовых",
+			b:    "Аналитики ожидают, что регулятор снизит ставку уже на следующем заседании совета директоров",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Distance(SimHash(tt.a), SimHash(tt.b))
+			if tt.duplicate && d > threshold {
+				t.Errorf("distance = %d, want <= %d", d, threshold)
+			}
+			// Unrelated texts are expected to be far from
+			// threshold, not just above it
+			if !tt.duplicate && d <= 2*threshold {
+				t.Errorf("distance = %d, want > %d", d, 2*threshold)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// Checkpoint is the backfill progress of a single channel within a job.
+type Checkpoint struct {
+	// Pagination resumes from messages older than this one
+	LastMessageId int64 `json:"last_message_id"`
+	Pages         int   `json:"pages"`
+	Messages      int   `json:"messages"`
+	Done          bool  `json:"done"`
+}
+
+func (s *Store) checkpointsKey(jobId string) string {
+	return fmt.Sprintf("%s:%s:checkpoints", s.key, jobId)
+}
+
+func (s *Store) SaveCheckpoint(
+	ctx context.Context,
+	jobId string,
+	chatId int64,
+	cp Checkpoint,
+) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	field := strconv.FormatInt(chatId, 10)
+	if err := s.rdb.HSet(ctx, s.checkpointsKey(jobId), field, data).Err(); err != nil {
+		return fmt.Errorf("failed to persist checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Checkpoints returns checkpoints of the job keyed by chat id.
+func (s *Store) Checkpoints(ctx context.Context, jobId string) (map[int64]Checkpoint, error) {
+	entries, err := s.rdb.HGetAll(ctx, s.checkpointsKey(jobId)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	checkpoints := make(map[int64]Checkpoint, len(entries))
+	for field, data := range entries {
+		chatId, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			zap.L().Error("Skipping malformed checkpoint", zap.String("chat", field))
+			continue
+		}
+
+		cp := Checkpoint{}
+		if err := json.Unmarshal([]byte(data), &cp); err != nil {
+			zap.L().Error("Skipping malformed checkpoint", zap.String("chat", field), zap.Error(err))
+			continue
+		}
+		checkpoints[chatId] = cp
+	}
+
+	return checkpoints, nil
+}
+
+func (s *Store) DeleteCheckpoints(ctx context.Context, jobId string) error {
+	return s.rdb.Del(ctx, s.checkpointsKey(jobId)).Err()
+}
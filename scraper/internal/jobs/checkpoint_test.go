@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	want := map[int64]Checkpoint{
+		-1001: {LastMessageId: 5 << 20, Pages: 2, Messages: 150},
+		-1002: {LastMessageId: 9 << 20, Pages: 1, Messages: 20, Done: true},
+	}
+	for chatId, cp := range want {
+		if err := store.SaveCheckpoint(ctx, "job", chatId, cp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Later checkpoint of the same channel replaces previous
+	want[-1001] = Checkpoint{LastMessageId: 3 << 20, Pages: 3, Messages: 250}
+	if err := store.SaveCheckpoint(ctx, "job", -1001, want[-1001]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveCheckpoint(ctx, "other", -1001, Checkpoint{Pages: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Checkpoints(ctx, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Checkpoints() = %v, want %v", got, want)
+	}
+
+	if err := store.DeleteCheckpoints(ctx, "job"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = store.Checkpoints(ctx, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Checkpoints() after delete = %v, want empty", got)
+	}
+
+	other, err := store.Checkpoints(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 1 {
+		t.Errorf("checkpoints of other job = %v, want them kept", other)
+	}
+}
+
+func TestCheckpointsMalformed(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	key := store.checkpointsKey("job")
+	if err := store.rdb.HSet(ctx, key, "chat", `{"pages":1}`, "-1001", "{").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveCheckpoint(ctx, "job", -1002, Checkpoint{Pages: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Checkpoints(ctx, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[-1002].Pages != 1 {
+		t.Errorf("Checkpoints() = %v, want only valid checkpoint", got)
+	}
+}
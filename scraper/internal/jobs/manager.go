@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"go.uber.org/zap"
+)
+
+type RunFunc func(ctx context.Context, job *pb.FetchJob) error
+
+// Manager runs fetch jobs in background and keeps their state in Store.
+type Manager struct {
+	Store *Store
+
+	ctx     context.Context
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func NewManager(ctx context.Context, store *Store) *Manager {
+	return &Manager{
+		Store:   store,
+		ctx:     ctx,
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Resume restarts jobs that were pending or running when the process stopped.
+// It's up to run to continue from the checkpoints saved by the previous run.
+func (m *Manager) Resume(ctx context.Context, run RunFunc) error {
+	jobs, err := m.Store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if !active(job) {
+			continue
+		}
+
+		if err := m.Start(job, run); err != nil {
+			return err
+		}
+
+		zap.L().Info("Resumed interrupted job", zap.String("id", job.JobId))
+	}
+
+	return nil
+}
+
+func (m *Manager) Start(job *pb.FetchJob, run RunFunc) error {
+	job.Status = pb.JobStatus_JOB_STATUS_PENDING
+	if err := m.Store.Save(m.ctx, job); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+
+	m.mu.Lock()
+	m.cancels[job.JobId] = cancel
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			m.mu.Lock()
+			delete(m.cancels, job.JobId)
+			m.mu.Unlock()
+			cancel()
+		}()
+
+		m.run(ctx, job, run)
+	}()
+
+	return nil
+}
+
+func (m *Manager) run(ctx context.Context, job *pb.FetchJob, run RunFunc) {
+	// Job state is saved with background context, so that
+	// final status is persisted even if job is cancelled
+	save := func() {
+		if err := m.Store.Save(context.Background(), job); err != nil {
+			zap.L().Error("Unable to save job", zap.String("id", job.JobId), zap.Error(err))
+		}
+	}
+
+	job.Status = pb.JobStatus_JOB_STATUS_RUNNING
+	save()
+
+	err := run(ctx, job)
+
+	switch {
+	case m.ctx.Err() != nil:
+		// Process is stopping, job stays running to be
+		// recovered on the next start
+		zap.L().Info("Fetch job interrupted", zap.String("id", job.JobId))
+		return
+	case ctx.Err() != nil:
+		job.Status = pb.JobStatus_JOB_STATUS_CANCELLED
+	case err != nil:
+		message := err.Error()
+		job.Status = pb.JobStatus_JOB_STATUS_FAILED
+		job.Error = &message
+	default:
+		job.Status = pb.JobStatus_JOB_STATUS_DONE
+	}
+	save()
+
+	if err := m.Store.DeleteCheckpoints(context.Background(), job.JobId); err != nil {
+		zap.L().Error("Unable to delete checkpoints", zap.String("id", job.JobId), zap.Error(err))
+	}
+
+	zap.L().Info(
+		"Fetch job finished",
+		zap.String("id", job.JobId),
+		zap.String("status", job.Status.String()),
+	)
+}
+
+// Cancel stops the job if it's running in this process and reports whether
+// it was.
+func (m *Manager) Cancel(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cancel, ok := m.cancels[id]
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
+
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+func active(job *pb.FetchJob) bool {
+	return job.Status == pb.JobStatus_JOB_STATUS_PENDING ||
+		job.Status == pb.JobStatus_JOB_STATUS_RUNNING
+}
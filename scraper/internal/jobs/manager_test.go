@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewStore(rdb, "test:jobs")
+}
+
+func TestManagerStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    RunFunc
+		cancel bool
+		want   pb.JobStatus
+	}{
+		{
+			name: "done",
+			run:  func(ctx context.Context, job *pb.FetchJob) error { return nil },
+			want: pb.JobStatus_JOB_STATUS_DONE,
+		},
+		{
+			name: "failed",
+			run: func(ctx context.Context, job *pb.FetchJob) error {
+				return errors.New("boom")
+			},
+			want: pb.JobStatus_JOB_STATUS_FAILED,
+		},
+		{
+			name: "cancelled",
+			run: func(ctx context.Context, job *pb.FetchJob) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			cancel: true,
+			want:   pb.JobStatus_JOB_STATUS_CANCELLED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+			m := NewManager(context.Background(), store)
+
+			if err := m.Start(&pb.FetchJob{JobId: tt.name}, tt.run); err != nil {
+				t.Fatal(err)
+			}
+			if tt.cancel && !m.Cancel(tt.name) {
+				t.Fatal("job is not running")
+			}
+			m.Wait()
+
+			if m.Cancel(tt.name) {
+				t.Error("finished job is still cancellable")
+			}
+
+			saved, err := store.Get(context.Background(), tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Status != tt.want {
+				t.Errorf("status = %v, want %v", saved.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagerResume(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	jobs := []*pb.FetchJob{
+		{JobId: "running", Status: pb.JobStatus_JOB_STATUS_RUNNING},
+		{JobId: "pending", Status: pb.JobStatus_JOB_STATUS_PENDING},
+		{JobId: "done", Status: pb.JobStatus_JOB_STATUS_DONE},
+	}
+	for _, job := range jobs {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resumed := make(chan string, len(jobs))
+	m := NewManager(ctx, store)
+	err := m.Resume(ctx, func(ctx context.Context, job *pb.FetchJob) error {
+		resumed <- job.JobId
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Wait()
+	close(resumed)
+
+	got := map[string]bool{}
+	for id := range resumed {
+		got[id] = true
+	}
+	if len(got) != 2 || !got["running"] || !got["pending"] {
+		t.Errorf("resumed = %v, want running and pending", got)
+	}
+}
+
+func TestManagerShutdownKeepsJobRunning(t *testing.T) {
+	store := newTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewManager(ctx, store)
+
+	err := m.Start(&pb.FetchJob{JobId: "job"}, func(ctx context.Context, job *pb.FetchJob) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	m.Wait()
+
+	saved, err := store.Get(context.Background(), "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != pb.JobStatus_JOB_STATUS_RUNNING {
+		t.Errorf("status = %v, want RUNNING", saved.Status)
+	}
+}
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrNotFound = errors.New("job not found")
+
+// Store persists fetch jobs in a Redis hash (job id -> job).
+type Store struct {
+	rdb *redis.Client
+	key string
+}
+
+func NewStore(rdb *redis.Client, key string) *Store {
+	return &Store{
+		rdb: rdb,
+		key: key,
+	}
+}
+
+func (s *Store) Save(ctx context.Context, job *pb.FetchJob) error {
+	job.UpdatedAt = timestamppb.Now()
+
+	data, err := protojson.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rdb.HSet(ctx, s.key, job.JobId, data).Err(); err != nil {
+		return fmt.Errorf("failed to persist job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*pb.FetchJob, error) {
+	data, err := s.rdb.HGet(ctx, s.key, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job: %w", err)
+	}
+
+	job := &pb.FetchJob{}
+	if err := protojson.Unmarshal([]byte(data), job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *Store) List(ctx context.Context) ([]*pb.FetchJob, error) {
+	entries, err := s.rdb.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	jobs := make([]*pb.FetchJob, 0, len(entries))
+	for id, data := range entries {
+		job := &pb.FetchJob{}
+		if err := protojson.Unmarshal([]byte(data), job); err != nil {
+			zap.L().Error("Skipping malformed job", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
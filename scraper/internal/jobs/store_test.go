@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStoreSaveGet(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	job := &pb.FetchJob{
+		JobId:             "job",
+		Status:            pb.JobStatus_JOB_STATUS_RUNNING,
+		ChatFolderLink:    "https://t.me/addlist/abc",
+		ChannelsTotal:     3,
+		MessagesCollected: 42,
+		ResultPrefix:      "jobs/job/",
+	}
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if job.UpdatedAt == nil {
+		t.Error("Save() doesn't set updated_at")
+	}
+
+	got, err := store.Get(ctx, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, job) {
+		t.Errorf("Get() = %v, want %v", got, job)
+	}
+}
+
+func TestStoreGetMissing(t *testing.T) {
+	_, err := newTestStore(t).Get(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestStoreList(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	for _, id := range []string{"a", "b"} {
+		if err := store.Save(ctx, &pb.FetchJob{JobId: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.rdb.HSet(ctx, store.key, "broken", "{").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.JobId
+	}
+	sort.Strings(ids)
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("List() = %v, want [a b] without malformed job", ids)
+	}
+}
@@ -0,0 +1,228 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
+	"github.com/zelenin/go-tdlib/client"
+	"go.uber.org/zap"
+)
+
+var ErrMediaSkipped = errors.New("media skipped")
+
+// mediaExtensions maps accepted MIME types to extensions of media keys. Media
+// of other types is stored without extension.
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+	"video/mp4":       ".mp4",
+	"audio/mpeg":      ".mp3",
+	"audio/ogg":       ".ogg",
+}
+
+// MediaUploader downloads message attachments through TDLib and puts them
+// to the same bucket message batches are written to.
+type MediaUploader struct {
+	tlClient  *client.Client
+	s3Client  *s3.S3
+	bucket    string
+	prefix    string
+	maxSize   int64
+	mimeTypes []string
+
+	queue chan mediaTask
+}
+
+type mediaTask struct {
+	file     *client.File
+	mimeType string
+	message  *pb.Message
+	out      chan<- *pb.Message
+}
+
+func NewMediaUploader(
+	tlClient *client.Client,
+	s3Client *s3.S3,
+	bucket string,
+	prefix string,
+	maxSize int64,
+	mimeTypes []string,
+	queueSize int,
+) *MediaUploader {
+	return &MediaUploader{
+		tlClient:  tlClient,
+		s3Client:  s3Client,
+		bucket:    bucket,
+		prefix:    prefix,
+		maxSize:   maxSize,
+		mimeTypes: mimeTypes,
+		queue:     make(chan mediaTask, queueSize),
+	}
+}
+
+// Run uploads media enqueued with Enqueue using the given number of workers
+// until context is done.
+func (m *MediaUploader) Run(ctx context.Context, workers int) {
+	wg := sync.WaitGroup{}
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case task := <-m.queue:
+					m.process(ctx, task)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Enqueue schedules upload of the message attachment without blocking the
+// caller. Once uploaded, message is updated to reference the media and sent
+// to out as an edit, so it must not be shared with other goroutines.
+// Attachment is skipped if the queue is full.
+func (m *MediaUploader) Enqueue(
+	file *client.File,
+	mimeType string,
+	message *pb.Message,
+	out chan<- *pb.Message,
+) {
+	if !m.accepts(file, mimeType) {
+		return
+	}
+
+	task := mediaTask{
+		file:     file,
+		mimeType: mimeType,
+		message:  message,
+		out:      out,
+	}
+
+	select {
+	case m.queue <- task:
+	default:
+		zap.L().Warn(
+			"Media queue is full, skipping attachment",
+			zap.Int64("chat_id", message.ChatId),
+			zap.Int64("message_id", message.MessageId),
+		)
+	}
+}
+
+func (m *MediaUploader) process(ctx context.Context, task mediaTask) {
+	key, err := m.Upload(
+		ctx,
+		task.file,
+		task.mimeType,
+		task.message.ChatId,
+		task.message.MessageId,
+	)
+	if errors.Is(err, ErrMediaSkipped) {
+		return
+	}
+	if err != nil {
+		zap.L().Error("Unable to upload media", zap.Error(err))
+		return
+	}
+
+	update := task.message
+	update.MediaKey = &key
+	update.MediaMimeType = &task.mimeType
+	update.ChangeType = pb.ChangeType_CHANGE_TYPE_EDITED
+
+	select {
+	case task.out <- update:
+	case <-ctx.Done():
+	}
+}
+
+func (m *MediaUploader) accepts(file *client.File, mimeType string) bool {
+	if !slices.Contains(m.mimeTypes, mimeType) {
+		return false
+	}
+
+	return max(file.Size, file.ExpectedSize) <= m.maxSize
+}
+
+// Upload stores the file under <prefix>media/<chat id>/<message id> and
+// returns its S3 key. ErrMediaSkipped is returned for files that don't pass
+// size or MIME type limits.
+func (m *MediaUploader) Upload(
+	ctx context.Context,
+	file *client.File,
+	mimeType string,
+	chatId int64,
+	messageId int64,
+) (string, error) {
+	if !m.accepts(file, mimeType) {
+		return "", ErrMediaSkipped
+	}
+
+	downloaded, err := limiter.Call(ctx, func() (*client.File, error) {
+		return m.tlClient.DownloadFile(&client.DownloadFileRequest{
+			FileId:      file.Id,
+			Priority:    1,
+			Synchronous: true,
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+	defer func() {
+		_, err := limiter.Call(ctx, func() (*client.Ok, error) {
+			return m.tlClient.DeleteFile(&client.DeleteFileRequest{
+				FileId: file.Id,
+			})
+		})
+		if err != nil {
+			zap.L().Debug("Unable to delete local file", zap.Error(err))
+		}
+	}()
+
+	if downloaded.Local == nil || !downloaded.Local.IsDownloadingCompleted {
+		return "", errors.New("file download is not completed")
+	}
+
+	f, err := os.Open(downloaded.Local.Path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	key := fmt.Sprintf(
+		"%smedia/%d/%d%s",
+		m.prefix,
+		chatId,
+		messageId,
+		mediaExtensions[mimeType],
+	)
+
+	_, err = m.s3Client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(m.bucket),
+		Key:         aws.String(key),
+		Body:        f,
+		ContentType: aws.String(mimeType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media to S3: %w", err)
+	}
+
+	zap.L().Debug("Uploaded media", zap.String("key", key))
+
+	return key, nil
+}
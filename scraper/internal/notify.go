@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	NotifyPubSub = "pubsub"
+	NotifyStream = "stream"
+)
+
+// Notifier delivers batch notifications to consumers.
+type Notifier interface {
+	Notify(ctx context.Context, payload []byte) error
+}
+
+// PubSubNotifier publishes notifications to Redis channel. Notifications are
+// lost if nobody is subscribed at the moment.
+type PubSubNotifier struct {
+	rdb     *redis.Client
+	channel string
+}
+
+func NewPubSubNotifier(rdb *redis.Client, channel string) *PubSubNotifier {
+	return &PubSubNotifier{
+		rdb:     rdb,
+		channel: channel,
+	}
+}
+
+func (p *PubSubNotifier) Notify(ctx context.Context, payload []byte) error {
+	return p.rdb.Publish(ctx, p.channel, payload).Err()
+}
+
+// StreamNotifier appends notifications to Redis stream, so that consumers
+// can read them with consumer groups and replay the ones missed during
+// downtime.
+type StreamNotifier struct {
+	rdb    *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewStreamNotifier(
+	ctx context.Context,
+	rdb *redis.Client,
+	stream string,
+	maxLen int64,
+	group string,
+) (*StreamNotifier, error) {
+	// Group is created from the beginning of the stream, so
+	// that consumer started after scraper doesn't miss anything
+	if group != "" {
+		err := rdb.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return nil, fmt.Errorf("failed to create consumer group: %w", err)
+		}
+		zap.L().Info(
+			"Ensured consumer group",
+			zap.String("stream", stream),
+			zap.String("group", group),
+		)
+	}
+
+	return &StreamNotifier{
+		rdb:    rdb,
+		stream: stream,
+		maxLen: maxLen,
+	}, nil
+}
+
+func (s *StreamNotifier) Notify(ctx context.Context, payload []byte) error {
+	return s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]any{"notification": payload},
+	}).Err()
+}
+
+func NewNotifier(
+	ctx context.Context,
+	rdb *redis.Client,
+	mode string,
+	channel string,
+	maxLen int64,
+	group string,
+) (Notifier, error) {
+	switch mode {
+	case NotifyPubSub:
+		return NewPubSubNotifier(rdb, channel), nil
+	case NotifyStream:
+		return NewStreamNotifier(ctx, rdb, channel, maxLen, group)
+	default:
+		return nil, fmt.Errorf("unknown notification mode %q", mode)
+	}
+}
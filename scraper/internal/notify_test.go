@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// commandRecorder keeps arguments of commands sent by the client.
+type commandRecorder struct {
+	commands [][]string
+}
+
+func (r *commandRecorder) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (r *commandRecorder) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		args := make([]string, len(cmd.Args()))
+		for i, arg := range cmd.Args() {
+			args[i] = fmt.Sprint(arg)
+		}
+		r.commands = append(r.commands, args)
+		return next(ctx, cmd)
+	}
+}
+
+func (r *commandRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func newTestRedis(t *testing.T) (*redis.Client, *commandRecorder) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	recorder := &commandRecorder{}
+	rdb.AddHook(recorder)
+
+	return rdb, recorder
+}
+
+func TestStreamNotifierExistingGroup(t *testing.T) {
+	ctx := context.Background()
+	rdb, _ := newTestRedis(t)
+
+	if err := rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: "inbrief",
+		Values: map[string]any{"notification": "early"},
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if _, err := NewNotifier(ctx, rdb, NotifyStream, "inbrief", 100, "clusterer"); err != nil {
+			t.Fatalf("NewNotifier() error = %v, want existing group to be reused", err)
+		}
+	}
+
+	streams, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    "clusterer",
+		Consumer: "test",
+		Streams:  []string{"inbrief", ">"},
+		Count:    10,
+	}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 1 || len(streams[0].Messages) != 1 {
+		t.Fatalf("XREADGROUP = %v, want entry added before group was created", streams)
+	}
+}
+
+func TestStreamNotifierTrims(t *testing.T) {
+	ctx := context.Background()
+	rdb, recorder := newTestRedis(t)
+
+	notifier, err := NewNotifier(ctx, rdb, NotifyStream, "inbrief", 3, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		if err := notifier.Notify(ctx, []byte(fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"xadd", "inbrief", "maxlen", "~", "3", "*"}
+	last := recorder.commands[len(recorder.commands)-1]
+	if !slices.Equal(last[:len(want)], want) {
+		t.Errorf("command = %v, want %v", last, want)
+	}
+
+	length, err := rdb.XLen(ctx, "inbrief").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length > 5 || length < 3 {
+		t.Errorf("stream length = %d, want between 3 and 5", length)
+	}
+}
+
+func TestNewNotifierUnknownMode(t *testing.T) {
+	rdb, _ := newTestRedis(t)
+
+	if _, err := NewNotifier(context.Background(), rdb, "kafka", "inbrief", 0, ""); err == nil {
+		t.Error("NewNotifier(kafka) error = nil, want error")
+	}
+}
@@ -2,15 +2,27 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/subscription"
 	"github.com/nrydanov/inbrief/internal/tl"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
+	"github.com/nrydanov/inbrief/pkg/channels"
 
 	connect "connectrpc.com/connect"
 
 	"github.com/zelenin/go-tdlib/client"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errNoSubscriptions = connect.NewError(
+	connect.CodeFailedPrecondition,
+	errors.New("subscriptions require streaming mode"),
 )
 
 func (s server) Fetch(
@@ -19,11 +31,7 @@ func (s server) Fetch(
 ) (*connect.Response[fetcher.FetchResponse], error) {
 	state := s.state
 	resp := &fetcher.FetchResponse{}
-	info, err := state.TlClient.CheckChatFolderInviteLink(
-		&client.CheckChatFolderInviteLinkRequest{
-			InviteLink: req.Msg.ChatFolderLink,
-		},
-	)
+	info, err := tl.CheckChatFolder(ctx, state.TlClient, req.Msg.ChatFolderLink)
 	if err != nil {
 		return nil, err
 	}
@@ -32,20 +40,35 @@ func (s server) Fetch(
 
 	zap.L().Debug("Scraping channels", zap.String("ids", fmt.Sprintf("%+v", ids)))
 
-	for _, id := range ids {
+	results := make([][]*fetcher.Message, len(ids))
+	resp.Channels = make([]*fetcher.ChannelStatus, len(ids))
+
+	channels.ForEach(ctx, ids, s.workers, func(i int, id tl.ChatId) {
 		msgs, err := tl.FetchChannel(
+			ctx,
 			int64(id),
 			req.Msg.LeftBound.AsTime(),
 			req.Msg.RightBound.AsTime(),
 			state,
 		)
-		// TODO(nrydanov): Handle error
 		if err != nil {
-			continue
+			zap.L().Error(
+				"Unable to fetch channel",
+				zap.Int64("id", int64(id)),
+				zap.Error(err),
+			)
 		}
 
-		resp.Messages = append(resp.Messages, msgs...)
+		results[i] = msgs
+		resp.Channels[i] = tl.ChannelStatus(ctx, state.Chats, int64(id), len(msgs), err)
+	})
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, msgs := range results {
+		resp.Messages = append(resp.Messages, msgs...)
 	}
 
 	go func() {
@@ -58,23 +81,227 @@ func (s server) Fetch(
 	return connect.NewResponse(resp), nil
 }
 
+func (s server) FetchStream(
+	ctx context.Context,
+	req *connect.Request[fetcher.FetchRequest],
+	stream *connect.ServerStream[fetcher.FetchStreamResponse],
+) error {
+	state := s.state
+	info, err := tl.CheckChatFolder(ctx, state.TlClient, req.Msg.ChatFolderLink)
+	if err != nil {
+		return err
+	}
+
+	ids := tl.ExtractChatIds(info)
+
+	zap.L().Debug("Streaming channels", zap.String("ids", fmt.Sprintf("%+v", ids)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Channels are fetched concurrently, but stream isn't
+	// safe for concurrent use, so every send is done under the lock
+	mu := sync.Mutex{}
+	done := 0
+	var sendErr error
+	send := func(resp *fetcher.FetchStreamResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if sendErr != nil {
+			return sendErr
+		}
+		if p := resp.GetProgress(); p != nil {
+			done++
+			p.ChannelsDone = int32(done)
+		}
+		if err := stream.Send(resp); err != nil {
+			sendErr = err
+			cancel()
+		}
+		return sendErr
+	}
+
+	channels.ForEach(ctx, ids, s.workers, func(_ int, id tl.ChatId) {
+		count := 0
+		err := tl.FetchChannelPages(
+			ctx,
+			int64(id),
+			0,
+			req.Msg.LeftBound.AsTime(),
+			req.Msg.RightBound.AsTime(),
+			state,
+			func(msgs []*fetcher.Message) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				count += len(msgs)
+
+				// Messages are handed over to writer before
+				// the chunk is sent, so that pages of a channel keep order
+				for _, msg := range msgs {
+					select {
+					case s.msgCh <- msg:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				return send(&fetcher.FetchStreamResponse{
+					Payload: &fetcher.FetchStreamResponse_Chunk{
+						Chunk: &fetcher.MessageChunk{
+							ChatId:   int64(id),
+							Messages: msgs,
+						},
+					},
+				})
+			},
+		)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			zap.L().Error(
+				"Unable to fetch channel",
+				zap.Int64("id", int64(id)),
+				zap.Error(err),
+			)
+		}
+
+		_ = send(&fetcher.FetchStreamResponse{
+			Payload: &fetcher.FetchStreamResponse_Progress{
+				Progress: &fetcher.FetchProgress{
+					ChatId:        int64(id),
+					ChannelsTotal: int32(len(ids)),
+					Status:        tl.ChannelStatus(ctx, state.Chats, int64(id), count, err),
+				},
+			},
+		})
+	})
+
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return ctx.Err()
+}
+
 func (s server) SubscribeChat(
 	ctx context.Context,
 	req *connect.Request[fetcher.SubscribeChatFolderRequest],
 ) (*connect.Response[fetcher.Empty], error) {
 	state := s.state
 
-	info, err := state.TlClient.CheckChatFolderInviteLink(
-		&client.CheckChatFolderInviteLinkRequest{
-			InviteLink: req.Msg.ChatFolderLink,
-		},
+	if state.Subscriptions == nil {
+		return nil, errNoSubscriptions
+	}
+
+	info, err := tl.CheckChatFolder(ctx, state.TlClient, req.Msg.ChatFolderLink)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := tl.JoinChatFolder(ctx, state.TlClient, req.Msg.ChatFolderLink, info)
+	if err != nil {
+		return nil, err
+	}
+
+	chatIds := make([]int64, len(ids))
+	for i, id := range ids {
+		chatIds[i] = int64(id)
+	}
+
+	err = state.Subscriptions.Add(ctx, &subscription.Subscription{
+		ChatFolderLink: req.Msg.ChatFolderLink,
+		ChatIds:        chatIds,
+		Usernames:      tl.ResolveUsernames(ctx, state.Chats, ids),
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Info(
+		"Subscribed to chat folder",
+		zap.String("link", req.Msg.ChatFolderLink),
+		zap.Int("chats", len(chatIds)),
 	)
 
-	_ = tl.ExtractChatIds(info)
+	return connect.NewResponse(&fetcher.Empty{}), nil
+}
+
+func (s server) UnsubscribeChatFolder(
+	ctx context.Context,
+	req *connect.Request[fetcher.UnsubscribeChatFolderRequest],
+) (*connect.Response[fetcher.UnsubscribeChatFolderResponse], error) {
+	state := s.state
+
+	if state.Subscriptions == nil {
+		return nil, errNoSubscriptions
+	}
 
+	sub, err := state.Subscriptions.Remove(ctx, req.Msg.ChatFolderLink)
+	if errors.Is(err, subscription.ErrNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return connect.NewResponse[fetcher.Empty](nil), nil
+	zap.L().Info(
+		"Unsubscribed from chat folder",
+		zap.String("link", sub.ChatFolderLink),
+	)
+
+	if req.Msg.LeaveChats {
+		for _, id := range sub.ChatIds {
+			if state.Subscriptions.Has(id) {
+				continue
+			}
+
+			_, err := limiter.Call(ctx, func() (*client.Ok, error) {
+				return state.TlClient.LeaveChat(&client.LeaveChatRequest{
+					ChatId: id,
+				})
+			})
+			if err != nil {
+				zap.L().Error(
+					"Unable to leave chat",
+					zap.Int64("id", id),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return connect.NewResponse(&fetcher.UnsubscribeChatFolderResponse{
+		Subscription: subscriptionToProto(sub),
+	}), nil
+}
+
+func (s server) ListSubscriptions(
+	ctx context.Context,
+	req *connect.Request[fetcher.ListSubscriptionsRequest],
+) (*connect.Response[fetcher.ListSubscriptionsResponse], error) {
+	state := s.state
+
+	if state.Subscriptions == nil {
+		return nil, errNoSubscriptions
+	}
+
+	resp := &fetcher.ListSubscriptionsResponse{}
+	for _, sub := range state.Subscriptions.List() {
+		resp.Subscriptions = append(resp.Subscriptions, subscriptionToProto(sub))
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func subscriptionToProto(sub *subscription.Subscription) *fetcher.Subscription {
+	return &fetcher.Subscription{
+		ChatFolderLink: sub.ChatFolderLink,
+		ChatIds:        sub.ChatIds,
+		Usernames:      sub.Usernames,
+		CreatedAt:      timestamppb.New(sub.CreatedAt),
+	}
 }
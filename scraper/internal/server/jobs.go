@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/jobs"
+	"github.com/nrydanov/inbrief/internal/sink"
+	"github.com/nrydanov/inbrief/internal/tl"
+	"github.com/nrydanov/inbrief/pkg/channels"
+
+	connect "connectrpc.com/connect"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errNoJobs = connect.NewError(
+	connect.CodeFailedPrecondition,
+	errors.New("fetch jobs require streaming mode"),
+)
+
+func (s server) StartFetchJob(
+	ctx context.Context,
+	req *connect.Request[fetcher.FetchRequest],
+) (*connect.Response[fetcher.StartFetchJobResponse], error) {
+	state := s.state
+
+	if state.Jobs == nil {
+		return nil, errNoJobs
+	}
+
+	id := req.Msg.GetRequestId()
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	_, err := state.Jobs.Store.Get(ctx, id)
+	if err == nil {
+		return nil, connect.NewError(
+			connect.CodeAlreadyExists,
+			fmt.Errorf("job %s already exists", id),
+		)
+	}
+	if !errors.Is(err, jobs.ErrNotFound) {
+		return nil, err
+	}
+
+	job := &fetcher.FetchJob{
+		JobId:          id,
+		ChatFolderLink: req.Msg.ChatFolderLink,
+		LeftBound:      req.Msg.LeftBound,
+		RightBound:     req.Msg.RightBound,
+		ResultPrefix:   fmt.Sprintf("jobs/%s/", id),
+		Status:         fetcher.JobStatus_JOB_STATUS_PENDING,
+		CreatedAt:      timestamppb.Now(),
+	}
+
+	// Job is updated by the runner once started, so response
+	// is built from a snapshot
+	snapshot := proto.Clone(job).(*fetcher.FetchJob)
+
+	if err := state.Jobs.Start(job, s.runFetchJob); err != nil {
+		return nil, err
+	}
+
+	zap.L().Info("Started fetch job", zap.String("id", id))
+
+	return connect.NewResponse(&fetcher.StartFetchJobResponse{
+		Job: snapshot,
+	}), nil
+}
+
+func (s server) GetFetchJob(
+	ctx context.Context,
+	req *connect.Request[fetcher.GetFetchJobRequest],
+) (*connect.Response[fetcher.GetFetchJobResponse], error) {
+	state := s.state
+
+	if state.Jobs == nil {
+		return nil, errNoJobs
+	}
+
+	job, err := state.Jobs.Store.Get(ctx, req.Msg.JobId)
+	if errors.Is(err, jobs.ErrNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&fetcher.GetFetchJobResponse{
+		Job: job,
+	}), nil
+}
+
+func (s server) CancelFetchJob(
+	ctx context.Context,
+	req *connect.Request[fetcher.CancelFetchJobRequest],
+) (*connect.Response[fetcher.CancelFetchJobResponse], error) {
+	state := s.state
+
+	if state.Jobs == nil {
+		return nil, errNoJobs
+	}
+
+	job, err := state.Jobs.Store.Get(ctx, req.Msg.JobId)
+	if errors.Is(err, jobs.ErrNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !state.Jobs.Cancel(job.JobId) {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			fmt.Errorf("job %s is not running", job.JobId),
+		)
+	}
+
+	zap.L().Info("Cancelled fetch job", zap.String("id", job.JobId))
+
+	// Final status is persisted by the runner asynchronously
+	job.Status = fetcher.JobStatus_JOB_STATUS_CANCELLED
+
+	return connect.NewResponse(&fetcher.CancelFetchJobResponse{
+		Job: job,
+	}), nil
+}
+
+// runFetchJob fetches channels of the job, writing every page to configured
+// sinks and checkpointing it, so that job interrupted by restart continues where it
+// stopped instead of fetching the whole history again.
+func (s server) runFetchJob(ctx context.Context, job *fetcher.FetchJob) error {
+	state := s.state
+	store := state.Jobs.Store
+
+	info, err := tl.CheckChatFolder(ctx, state.TlClient, job.ChatFolderLink)
+	if err != nil {
+		return err
+	}
+
+	ids := tl.ExtractChatIds(info)
+
+	checkpoints, err := store.Checkpoints(ctx, job.JobId)
+	if err != nil {
+		return err
+	}
+
+	// Progress is rebuilt from checkpoints, statuses of
+	// finished channels are kept from the previous run
+	job.ChannelsTotal = int32(len(ids))
+	job.ChannelsDone = 0
+	job.MessagesCollected = 0
+	statuses := make([]*fetcher.ChannelStatus, 0, len(ids))
+	for _, status := range job.Channels {
+		if checkpoints[status.ChatId].Done {
+			statuses = append(statuses, status)
+		}
+	}
+	job.Channels = statuses
+
+	pending := make([]tl.ChatId, 0, len(ids))
+	for _, id := range ids {
+		cp := checkpoints[int64(id)]
+		job.MessagesCollected += int64(cp.Messages)
+		if cp.Done {
+			job.ChannelsDone++
+			continue
+		}
+		pending = append(pending, id)
+	}
+
+	mu := sync.Mutex{}
+	update := func(fn func()) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fn()
+		if err := store.Save(ctx, job); err != nil {
+			zap.L().Error("Unable to save job progress", zap.Error(err))
+		}
+	}
+
+	update(func() {})
+
+	channels.ForEach(ctx, pending, s.workers, func(_ int, id tl.ChatId) {
+		cp := checkpoints[int64(id)]
+		if cp.Pages > 0 {
+			zap.L().Info(
+				"Resuming channel from checkpoint",
+				zap.String("job", job.JobId),
+				zap.Int64("id", int64(id)),
+				zap.Int64("message", cp.LastMessageId),
+			)
+		}
+
+		err := tl.FetchChannelPages(
+			ctx,
+			int64(id),
+			cp.LastMessageId,
+			job.LeftBound.AsTime(),
+			job.RightBound.AsTime(),
+			state,
+			func(page []*fetcher.Message) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				key := fmt.Sprintf("%s%d/%05d", job.ResultPrefix, id, cp.Pages)
+				_, err := state.Sink.Write(ctx, sink.Batch{
+					Id:        key,
+					Source:    sink.SourceFetch,
+					CreatedAt: time.Now(),
+					Messages:  page,
+					Key:       key,
+				})
+				if err != nil {
+					return err
+				}
+
+				cp.LastMessageId = page[len(page)-1].MessageId
+				cp.Pages++
+				cp.Messages += len(page)
+				if err := store.SaveCheckpoint(ctx, job.JobId, int64(id), cp); err != nil {
+					return err
+				}
+
+				update(func() {
+					job.MessagesCollected += int64(len(page))
+				})
+				return nil
+			},
+		)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			zap.L().Error(
+				"Unable to fetch channel",
+				zap.String("job", job.JobId),
+				zap.Int64("id", int64(id)),
+				zap.Error(err),
+			)
+		}
+
+		cp.Done = true
+		if err := store.SaveCheckpoint(ctx, job.JobId, int64(id), cp); err != nil {
+			zap.L().Error("Unable to save checkpoint", zap.Error(err))
+		}
+
+		status := tl.ChannelStatus(ctx, state.Chats, int64(id), cp.Messages, err)
+		update(func() {
+			job.ChannelsDone++
+			job.Channels = append(job.Channels, status)
+		})
+	})
+
+	return ctx.Err()
+}
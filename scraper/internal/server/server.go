@@ -14,8 +14,9 @@ import (
 )
 
 type server struct {
-	state *internal.AppState
-	msgCh chan *fetcher.Message
+	state   *internal.AppState
+	msgCh   chan *fetcher.Message
+	workers int
 }
 
 func StartServer(
@@ -24,10 +25,19 @@ func StartServer(
 	state *internal.AppState,
 	msgCh chan *fetcher.Message,
 ) {
-	path, handler := pc.NewFetcherServiceHandler(server{
-		state: state,
-		msgCh: msgCh,
-	})
+	srv := server{
+		state:   state,
+		msgCh:   msgCh,
+		workers: cfg.Server.FetchWorkers,
+	}
+
+	if state.Jobs != nil {
+		if err := state.Jobs.Resume(ctx, srv.runFetchJob); err != nil {
+			zap.L().Error("Failed to resume fetch jobs", zap.Error(err))
+		}
+	}
+
+	path, handler := pc.NewFetcherServiceHandler(srv)
 
 	mux := http.NewServeMux()
 	mux.Handle(path, handler)
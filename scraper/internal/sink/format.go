@@ -0,0 +1,244 @@
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/parquet-go/parquet-go"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Format describes how a batch is encoded. Name is also used as file
+// extension.
+type Format struct {
+	Name        string
+	ContentType string
+
+	encode func(w io.Writer, msgs []*pb.Message) error
+}
+
+const (
+	FormatJSON      = "json"
+	FormatJSONL     = "jsonl"
+	FormatJSONLGzip = "jsonl.gz"
+	FormatJSONLZstd = "jsonl.zst"
+	FormatProto     = "pb"
+	FormatParquet   = "parquet"
+)
+
+var formats = map[string]Format{
+	FormatJSON: {
+		Name:        FormatJSON,
+		ContentType: "application/json",
+		encode: func(w io.Writer, msgs []*pb.Message) error {
+			data, err := MarshalMessages(msgs)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		},
+	},
+	FormatJSONL: {
+		Name:        FormatJSONL,
+		ContentType: "application/x-ndjson",
+		encode:      encodeJSONL,
+	},
+	FormatJSONLGzip: {
+		Name:        FormatJSONLGzip,
+		ContentType: "application/gzip",
+		encode: func(w io.Writer, msgs []*pb.Message) error {
+			gw := gzip.NewWriter(w)
+			if err := encodeJSONL(gw, msgs); err != nil {
+				return err
+			}
+			return gw.Close()
+		},
+	},
+	FormatJSONLZstd: {
+		Name:        FormatJSONLZstd,
+		ContentType: "application/zstd",
+		encode: func(w io.Writer, msgs []*pb.Message) error {
+			zw, err := zstd.NewWriter(w)
+			if err != nil {
+				return err
+			}
+			if err := encodeJSONL(zw, msgs); err != nil {
+				zw.Close()
+				return err
+			}
+			return zw.Close()
+		},
+	},
+	// Every message is prefixed with its size as varint, see
+	// protodelim package
+	FormatProto: {
+		Name:        FormatProto,
+		ContentType: "application/x-protobuf",
+		encode: func(w io.Writer, msgs []*pb.Message) error {
+			for _, msg := range msgs {
+				if _, err := protodelim.MarshalTo(w, msg); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	FormatParquet: {
+		Name:        FormatParquet,
+		ContentType: "application/vnd.apache.parquet",
+		encode:      encodeParquet,
+	},
+}
+
+func ParseFormat(name string) (Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown batch format %q", name)
+	}
+
+	return format, nil
+}
+
+func (f Format) Encode(msgs []*pb.Message) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := f.encode(&buf, msgs); err != nil {
+		return nil, fmt.Errorf("failed to encode batch as %s: %w", f.Name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeJSONL(w io.Writer, msgs []*pb.Message) error {
+	marshaler := protojson.MarshalOptions{
+		EmitUnpopulated: true,
+	}
+
+	for _, msg := range msgs {
+		data, err := marshaler.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// row is a flattened message for columnar storage. Nested fields that are
+// rarely filtered on are kept as JSON.
+type row struct {
+	ChatId               int64      `parquet:"chat_id"`
+	MessageId            int64      `parquet:"message_id"`
+	Ts                   time.Time  `parquet:"ts,timestamp(millisecond)"`
+	ChangeType           string     `parquet:"change_type,enum"`
+	Text                 string     `parquet:"text"`
+	OriginalText         string     `parquet:"original_text"`
+	Link                 string     `parquet:"link"`
+	ChannelUsername      string     `parquet:"channel_username"`
+	ChannelTitle         string     `parquet:"channel_title"`
+	MediaType            string     `parquet:"media_type,enum"`
+	MediaKey             *string    `parquet:"media_key,optional"`
+	MediaMimeType        *string    `parquet:"media_mime_type,optional"`
+	SenderUserId         *int64     `parquet:"sender_user_id,optional"`
+	SenderChatId         *int64     `parquet:"sender_chat_id,optional"`
+	AuthorSignature      string     `parquet:"author_signature"`
+	ViewCount            int32      `parquet:"view_count"`
+	ForwardCount         int32      `parquet:"forward_count"`
+	ReactionCount        int32      `parquet:"reaction_count"`
+	Reactions            string     `parquet:"reactions,json"`
+	Entities             string     `parquet:"entities,json"`
+	ReplyToMessageId     *int64     `parquet:"reply_to_message_id,optional"`
+	EditedAt             *time.Time `parquet:"edited_at,optional"`
+	DuplicateOfChatId    *int64     `parquet:"duplicate_of_chat_id,optional"`
+	DuplicateOfMessageId *int64     `parquet:"duplicate_of_message_id,optional"`
+}
+
+func newRow(msg *pb.Message) (row, error) {
+	r := row{
+		ChatId:           msg.ChatId,
+		MessageId:        msg.MessageId,
+		Ts:               msg.Ts.AsTime(),
+		ChangeType:       msg.ChangeType.String(),
+		Text:             msg.Text,
+		OriginalText:     msg.OriginalText,
+		Link:             msg.Link,
+		ChannelUsername:  msg.ChannelUsername,
+		ChannelTitle:     msg.ChannelTitle,
+		MediaType:        msg.MediaType.String(),
+		MediaKey:         msg.MediaKey,
+		MediaMimeType:    msg.MediaMimeType,
+		ViewCount:        msg.ViewCount,
+		ForwardCount:     msg.ForwardCount,
+		ReactionCount:    msg.ReactionCount,
+		ReplyToMessageId: msg.ReplyToMessageId,
+	}
+
+	if sender := msg.Sender; sender != nil {
+		r.AuthorSignature = sender.AuthorSignature
+		switch id := sender.Id.(type) {
+		case *pb.Sender_UserId:
+			r.SenderUserId = &id.UserId
+		case *pb.Sender_ChatId:
+			r.SenderChatId = &id.ChatId
+		}
+	}
+
+	if msg.EditedAt != nil {
+		editedAt := msg.EditedAt.AsTime()
+		r.EditedAt = &editedAt
+	}
+
+	if ref := msg.DuplicateOf; ref != nil {
+		r.DuplicateOfChatId = &ref.ChatId
+		r.DuplicateOfMessageId = &ref.MessageId
+	}
+
+	reactions := msg.Reactions
+	if reactions == nil {
+		reactions = map[string]int32{}
+	}
+	data, err := json.Marshal(reactions)
+	if err != nil {
+		return row{}, err
+	}
+	r.Reactions = string(data)
+
+	entities := make([]json.RawMessage, len(msg.Entities))
+	for i, entity := range msg.Entities {
+		data, err := protojson.Marshal(entity)
+		if err != nil {
+			return row{}, err
+		}
+		entities[i] = data
+	}
+	data, err = json.Marshal(entities)
+	if err != nil {
+		return row{}, err
+	}
+	r.Entities = string(data)
+
+	return r, nil
+}
+
+func encodeParquet(w io.Writer, msgs []*pb.Message) error {
+	rows := make([]row, len(msgs))
+	for i, msg := range msgs {
+		r, err := newRow(msg)
+		if err != nil {
+			return err
+		}
+		rows[i] = r
+	}
+
+	return parquet.Write(w, rows, parquet.Compression(&parquet.Zstd))
+}
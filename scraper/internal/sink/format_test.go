@@ -0,0 +1,230 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/parquet-go/parquet-go"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testMessages() []*pb.Message {
+	ts := time.Date(2026, 5, 1, 12, 30, 0, 0, time.UTC)
+	mediaKey := "media/1/2.jpg"
+	replyTo := int64(1 << 20)
+
+	return []*pb.Message{
+		{
+			Ts:               timestamppb.New(ts),
+			ChatId:           -1001,
+			MessageId:        2 << 20,
+			Text:             "Первое сообщение",
+			ChangeType:       pb.ChangeType_CHANGE_TYPE_NEW,
+			MediaType:        pb.MediaType_MEDIA_TYPE_PHOTO,
+			MediaKey:         &mediaKey,
+			ChannelUsername:  "inbrief",
+			Reactions:        map[string]int32{"👍": 3},
+			ReactionCount:    3,
+			ReplyToMessageId: &replyTo,
+			Sender:           &pb.Sender{Id: &pb.Sender_ChatId{ChatId: -1001}},
+			Entities: []*pb.TextEntity{
+				{Type: pb.EntityType_ENTITY_TYPE_MENTION, Offset: 0, Length: 6, Value: "Первое"},
+			},
+		},
+		{
+			Ts:         timestamppb.New(ts.Add(time.Minute)),
+			ChatId:     -1001,
+			MessageId:  3 << 20,
+			Text:       "Second message",
+			ChangeType: pb.ChangeType_CHANGE_TYPE_EDITED,
+			EditedAt:   timestamppb.New(ts.Add(2 * time.Minute)),
+			DuplicateOf: &pb.MessageRef{
+				ChatId:    -1002,
+				MessageId: 1 << 20,
+			},
+		},
+	}
+}
+
+func decodeJSONL(t *testing.T, r io.Reader) []*pb.Message {
+	t.Helper()
+
+	msgs := make([]*pb.Message, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		msg := &pb.Message{}
+		if err := protojson.Unmarshal(scanner.Bytes(), msg); err != nil {
+			t.Fatalf("unable to decode line: %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return msgs
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		decode func(t *testing.T, data []byte) []*pb.Message
+	}{
+		{
+			format: FormatJSON,
+			decode: func(t *testing.T, data []byte) []*pb.Message {
+				raw := make([]json.RawMessage, 0)
+				if err := json.Unmarshal(data, &raw); err != nil {
+					t.Fatal(err)
+				}
+				msgs := make([]*pb.Message, len(raw))
+				for i, data := range raw {
+					msgs[i] = &pb.Message{}
+					if err := protojson.Unmarshal(data, msgs[i]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return msgs
+			},
+		},
+		{
+			format: FormatJSONL,
+			decode: func(t *testing.T, data []byte) []*pb.Message {
+				return decodeJSONL(t, bytes.NewReader(data))
+			},
+		},
+		{
+			format: FormatJSONLGzip,
+			decode: func(t *testing.T, data []byte) []*pb.Message {
+				r, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return decodeJSONL(t, r)
+			},
+		},
+		{
+			format: FormatJSONLZstd,
+			decode: func(t *testing.T, data []byte) []*pb.Message {
+				r, err := zstd.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer r.Close()
+				return decodeJSONL(t, r)
+			},
+		},
+		{
+			format: FormatProto,
+			decode: func(t *testing.T, data []byte) []*pb.Message {
+				r := bufio.NewReader(bytes.NewReader(data))
+				msgs := make([]*pb.Message, 0)
+				for {
+					msg := &pb.Message{}
+					err := protodelim.UnmarshalFrom(r, msg)
+					if err == io.EOF {
+						return msgs
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					msgs = append(msgs, msg)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			format, err := ParseFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := testMessages()
+			data, err := format.Encode(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := tt.decode(t, data)
+			if len(got) != len(want) {
+				t.Fatalf("decoded %d messages, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !proto.Equal(got[i], want[i]) {
+					t.Errorf("message %d = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatParquet(t *testing.T) {
+	format, err := ParseFormat(FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := testMessages()
+	data, err := format.Encode(msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.Read[row](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(msgs) {
+		t.Fatalf("decoded %d rows, want %d", len(rows), len(msgs))
+	}
+
+	for i, msg := range msgs {
+		r := rows[i]
+		if r.ChatId != msg.ChatId || r.MessageId != msg.MessageId || r.Text != msg.Text {
+			t.Errorf("row %d = %+v, want message %v", i, r, msg)
+		}
+		if r.ChangeType != msg.ChangeType.String() {
+			t.Errorf("row %d change type = %s, want %s", i, r.ChangeType, msg.ChangeType)
+		}
+		if !r.Ts.Equal(msg.Ts.AsTime()) {
+			t.Errorf("row %d ts = %s, want %s", i, r.Ts, msg.Ts.AsTime())
+		}
+	}
+
+	if rows[0].MediaKey == nil || *rows[0].MediaKey != *msgs[0].MediaKey {
+		t.Errorf("media key = %v, want %s", rows[0].MediaKey, *msgs[0].MediaKey)
+	}
+	if rows[0].SenderChatId == nil || *rows[0].SenderChatId != -1001 {
+		t.Errorf("sender chat id = %v, want -1001", rows[0].SenderChatId)
+	}
+	if rows[0].Reactions != `{"👍":3}` {
+		t.Errorf("reactions = %s, want {\"👍\":3}", rows[0].Reactions)
+	}
+	if rows[0].EditedAt != nil {
+		t.Errorf("edited at = %v, want nil", rows[0].EditedAt)
+	}
+	if rows[1].EditedAt == nil || !rows[1].EditedAt.Equal(msgs[1].EditedAt.AsTime()) {
+		t.Errorf("edited at = %v, want %s", rows[1].EditedAt, msgs[1].EditedAt.AsTime())
+	}
+	if rows[1].DuplicateOfMessageId == nil || *rows[1].DuplicateOfMessageId != 1<<20 {
+		t.Errorf("duplicate of = %v, want %d", rows[1].DuplicateOfMessageId, 1<<20)
+	}
+}
+
+func TestParseFormatUnknown(t *testing.T) {
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) error = nil, want error")
+	}
+}
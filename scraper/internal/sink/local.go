@@ -0,0 +1,62 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Local writes every batch to <dir>/<partitioned path>, mirroring the S3
+// layout, which is enough for local development without object storage.
+type Local struct {
+	dir    string
+	format Format
+}
+
+func NewLocal(dir string, format Format) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create sink directory: %w", err)
+	}
+
+	return &Local{
+		dir:    dir,
+		format: format,
+	}, nil
+}
+
+func (l *Local) Write(ctx context.Context, batch Batch) ([]Object, error) {
+	data, err := l.format.Encode(batch.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	key := batch.Path(l.format.Name)
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create partition directory: %w", err)
+	}
+
+	// File is renamed only when fully written, so that
+	// readers never see partial batches
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write batch: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write batch: %w", err)
+	}
+
+	return []Object{{
+		Sink:     KindLocal,
+		Bucket:   l.dir,
+		Key:      key,
+		Format:   l.format.Name,
+		Checksum: Checksum(data),
+	}}, nil
+}
+
+func (l *Local) Close() error {
+	return nil
+}
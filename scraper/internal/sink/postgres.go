@@ -0,0 +1,105 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Postgres appends every message as a row to the table, keeping the full
+// message as JSONB next to the columns needed for lookups.
+type Postgres struct {
+	pool  *pgxpool.Pool
+	table pgx.Identifier
+}
+
+func NewPostgres(ctx context.Context, dsn string, table string) (*Postgres, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+
+	p := &Postgres{
+		pool:  pool,
+		table: pgx.Identifier{table},
+	}
+
+	if err := p.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Postgres) migrate(ctx context.Context) error {
+	table := p.table.Sanitize()
+
+	_, err := p.pool.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			batch_id    TEXT        NOT NULL,
+			chat_id     BIGINT      NOT NULL,
+			message_id  BIGINT      NOT NULL,
+			change_type TEXT        NOT NULL,
+			ts          TIMESTAMPTZ,
+			payload     JSONB       NOT NULL,
+			written_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE INDEX IF NOT EXISTS %s ON %s (chat_id, message_id);
+	`,
+		table,
+		pgx.Identifier{p.table[0] + "_chat_message_idx"}.Sanitize(),
+		table,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to create messages table: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) Write(ctx context.Context, batch Batch) ([]Object, error) {
+	rows := make([][]any, 0, len(batch.Messages))
+	for _, msg := range batch.Messages {
+		payload, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		var ts *time.Time
+		if msg.Ts != nil {
+			t := msg.Ts.AsTime()
+			ts = &t
+		}
+
+		rows = append(rows, []any{
+			batch.Id,
+			msg.ChatId,
+			msg.MessageId,
+			msg.ChangeType.String(),
+			ts,
+			payload,
+		})
+	}
+
+	_, err := p.pool.CopyFrom(
+		ctx,
+		p.table,
+		[]string{"batch_id", "chat_id", "message_id", "change_type", "ts", "payload"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write messages to Postgres: %w", err)
+	}
+
+	return []Object{{Sink: KindPostgres, Bucket: p.table[0], Key: batch.Id}}, nil
+}
+
+func (p *Postgres) Close() error {
+	p.pool.Close()
+	return nil
+}
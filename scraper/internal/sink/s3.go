@@ -0,0 +1,94 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.uber.org/zap"
+)
+
+// S3 puts every batch to <bucket>/<prefix><partitioned path>. Format is
+// recorded in object metadata.
+type S3 struct {
+	client *s3.S3
+	bucket string
+	prefix string
+	format Format
+}
+
+func NewS3(client *s3.S3, bucket string, prefix string, format Format) *S3 {
+	return &S3{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+		format: format,
+	}
+}
+
+func (s *S3) Write(ctx context.Context, batch Batch) ([]Object, error) {
+	data, err := s.format.Encode(batch.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.prefix + batch.Path(s.format.Name)
+	checksum := Checksum(data)
+	_, err = s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(s.format.ContentType),
+		Metadata: map[string]*string{
+			"format":   aws.String(s.format.Name),
+			"messages": aws.String(strconv.Itoa(len(batch.Messages))),
+			"checksum": aws.String(checksum),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload messages to S3: %w", err)
+	}
+
+	return []Object{{
+		Sink:     KindS3,
+		Bucket:   s.bucket,
+		Key:      key,
+		Format:   s.format.Name,
+		Checksum: checksum,
+	}}, nil
+}
+
+func (s *S3) Close() error {
+	return nil
+}
+
+// EnsureBucket creates the bucket if it doesn't exist yet.
+func EnsureBucket(client *s3.S3, bucket string) error {
+	_, err := client.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err == nil {
+		return nil
+	}
+
+	var awsErr awserr.RequestFailure
+	if !errors.As(err, &awsErr) || awsErr.StatusCode() != 404 {
+		return fmt.Errorf("failed to check bucket: %w", err)
+	}
+
+	_, err = client.CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	zap.L().Info("Created bucket", zap.String("bucket", bucket))
+
+	return nil
+}
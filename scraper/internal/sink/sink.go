@@ -0,0 +1,208 @@
+package sink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/nrydanov/inbrief/config"
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	SourceStream = "stream"
+	SourceFetch  = "fetch"
+)
+
+// Batch is a set of messages flushed by Writer at once.
+type Batch struct {
+	Id        string
+	Source    string
+	CreatedAt time.Time
+	Messages  []*pb.Message
+	// Key replaces partitioned path if set, e.g. fetch job
+	// pages are written to jobs/<job id>/<chat id>/<page>
+	Key string
+}
+
+// Path returns Hive-style partitioned location of the batch, i.e.
+// dt=YYYY-MM-DD/hour=HH/source=<source>/<id>.<ext>, or <key>.<ext> for
+// batches with explicit key.
+func (b Batch) Path(ext string) string {
+	if b.Key != "" {
+		return b.Key + "." + ext
+	}
+
+	t := b.CreatedAt.UTC()
+	return fmt.Sprintf(
+		"dt=%s/hour=%02d/source=%s/%s.%s",
+		t.Format(time.DateOnly),
+		t.Hour(),
+		b.Source,
+		b.Id,
+		ext,
+	)
+}
+
+// Object describes where a sink has put the batch.
+type Object struct {
+	Sink string
+	// Bucket is S3 bucket, directory or table depending on
+	// the sink
+	Bucket string
+	Key    string
+	Format string
+	// Checksum is sha256:<hex> of the written bytes, empty
+	// for sinks that don't write files
+	Checksum string
+}
+
+// Sink persists message batches.
+type Sink interface {
+	Write(ctx context.Context, batch Batch) ([]Object, error)
+	Close() error
+}
+
+const (
+	KindS3       = "s3"
+	KindLocal    = "local"
+	KindPostgres = "postgres"
+)
+
+// FromConfig builds sinks listed in cfg. S3 client is only required if S3
+// sink is enabled.
+func FromConfig(
+	ctx context.Context,
+	cfg config.SinkConfig,
+	s3Client *s3.S3,
+	s3Cfg config.S3Config,
+) (Sink, error) {
+	format, err := ParseFormat(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := make([]Sink, 0, len(cfg.Kinds))
+	closeAll := func() {
+		for _, s := range sinks {
+			s.Close()
+		}
+	}
+
+	for _, kind := range cfg.Kinds {
+		switch kind {
+		case KindS3:
+			if s3Client == nil {
+				closeAll()
+				return nil, errors.New("S3 sink requires S3 client")
+			}
+			sinks = append(sinks, NewS3(s3Client, s3Cfg.Bucket, s3Cfg.Prefix, format))
+		case KindLocal:
+			s, err := NewLocal(cfg.Local.Dir, format)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+			sinks = append(sinks, s)
+		case KindPostgres:
+			s, err := NewPostgres(ctx, cfg.Postgres.DSN, cfg.Postgres.Table)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+			sinks = append(sinks, s)
+		default:
+			closeAll()
+			return nil, fmt.Errorf("unknown sink %q", kind)
+		}
+	}
+
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+
+	return NewFanout(sinks...), nil
+}
+
+// Enabled reports whether sink of the given kind is listed in cfg.
+func Enabled(cfg config.SinkConfig, kind string) bool {
+	return slices.Contains(cfg.Kinds, kind)
+}
+
+// Fanout writes every batch to all sinks concurrently. Batch is considered
+// written only if every sink succeeded. Objects are returned in the order
+// sinks were given.
+type Fanout struct {
+	sinks []Sink
+}
+
+func NewFanout(sinks ...Sink) *Fanout {
+	return &Fanout{sinks: sinks}
+}
+
+func (f *Fanout) Write(ctx context.Context, batch Batch) ([]Object, error) {
+	objects := make([][]Object, len(f.sinks))
+	errs := make([]error, len(f.sinks))
+
+	wg := sync.WaitGroup{}
+	for i, s := range f.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			objects[i], errs[i] = s.Write(ctx, batch)
+		}()
+	}
+	wg.Wait()
+
+	return slices.Concat(objects...), errors.Join(errs...)
+}
+
+func (f *Fanout) Close() error {
+	errs := make([]error, 0, len(f.sinks))
+	for _, s := range f.sinks {
+		errs = append(errs, s.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// MarshalMessages encodes messages as JSON array.
+func MarshalMessages(msgs []*pb.Message) ([]byte, error) {
+	marshaler := protojson.MarshalOptions{
+		UseEnumNumbers:  false,
+		EmitUnpopulated: true,
+		Indent:          "  ",
+	}
+
+	jsonMessages := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		jsonData, err := marshaler.Marshal(msg)
+		if err != nil {
+			zap.L().Error("Failed to marshal proto message", zap.Error(err))
+			return nil, err
+		}
+		jsonMessages[i] = json.RawMessage(jsonData)
+	}
+
+	marshalled, err := json.Marshal(jsonMessages)
+	if err != nil {
+		zap.L().Error("Failed to marshal messages", zap.Error(err))
+		return nil, err
+	}
+
+	return marshalled, nil
+}
@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBatchPath(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name  string
+		batch Batch
+		ext   string
+		want  string
+	}{
+		{
+			name: "stream",
+			batch: Batch{
+				Id:        "a1",
+				Source:    SourceStream,
+				CreatedAt: time.Date(2026, 5, 1, 9, 15, 0, 0, time.UTC),
+			},
+			ext:  "json",
+			want: "dt=2026-05-01/hour=09/source=stream/a1.json",
+		},
+		{
+			name: "fetch",
+			batch: Batch{
+				Id:        "b2",
+				Source:    SourceFetch,
+				CreatedAt: time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC),
+			},
+			ext:  "jsonl.gz",
+			want: "dt=2026-12-31/hour=23/source=fetch/b2.jsonl.gz",
+		},
+		{
+			name: "partitioned in UTC",
+			batch: Batch{
+				Id:        "c3",
+				Source:    SourceStream,
+				CreatedAt: time.Date(2026, 1, 1, 1, 0, 0, 0, moscow),
+			},
+			ext:  "parquet",
+			want: "dt=2025-12-31/hour=22/source=stream/c3.parquet",
+		},
+		{
+			name: "explicit key",
+			batch: Batch{
+				Id:        "d4",
+				Source:    SourceFetch,
+				CreatedAt: time.Date(2026, 5, 1, 9, 15, 0, 0, time.UTC),
+				Key:       "jobs/j1/-1001/00003",
+			},
+			ext:  "jsonl",
+			want: "jobs/j1/-1001/00003.jsonl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.batch.Path(tt.ext); got != tt.want {
+				t.Errorf("Path() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalWrite(t *testing.T) {
+	dir := t.TempDir()
+	format, err := ParseFormat(FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := NewLocal(dir, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := Batch{
+		Id:        "a1",
+		Source:    SourceStream,
+		CreatedAt: time.Date(2026, 5, 1, 9, 15, 0, 0, time.UTC),
+		Messages:  testMessages(),
+	}
+	objects, err := local.Write(context.Background(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("got %d objects, want 1", len(objects))
+	}
+
+	object := objects[0]
+	if object.Sink != KindLocal || object.Bucket != dir || object.Key != batch.Path(FormatJSONL) {
+		t.Errorf("object = %+v, want local object at %s", object, batch.Path(FormatJSONL))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(object.Key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeJSONL(t, bytes.NewReader(data)); len(got) != len(batch.Messages) {
+		t.Errorf("got %d messages, want %d", len(got), len(batch.Messages))
+	}
+	if object.Checksum != Checksum(data) {
+		t.Errorf("checksum = %s, want %s", object.Checksum, Checksum(data))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(object.Key)) + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file is left behind: %v", err)
+	}
+}
+
+type stubSink struct {
+	object Object
+	err    error
+}
+
+func (s stubSink) Write(ctx context.Context, batch Batch) ([]Object, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []Object{s.object}, nil
+}
+
+func (s stubSink) Close() error {
+	return nil
+}
+
+func TestFanout(t *testing.T) {
+	first := Object{Sink: KindS3, Key: "a"}
+	second := Object{Sink: KindLocal, Key: "b"}
+
+	objects, err := NewFanout(stubSink{object: first}, stubSink{object: second}).
+		Write(context.Background(), Batch{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 || objects[0] != first || objects[1] != second {
+		t.Errorf("objects = %+v, want objects in order of sinks", objects)
+	}
+
+	failure := errors.New("unavailable")
+	_, err = NewFanout(stubSink{object: first}, stubSink{err: failure}).
+		Write(context.Background(), Batch{})
+	if !errors.Is(err, failure) {
+		t.Errorf("error = %v, want %v", err, failure)
+	}
+}
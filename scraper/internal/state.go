@@ -3,6 +3,11 @@ package internal
 import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/chats"
+	"github.com/nrydanov/inbrief/internal/jobs"
+	"github.com/nrydanov/inbrief/internal/sink"
+	"github.com/nrydanov/inbrief/internal/subscription"
+	"github.com/nrydanov/inbrief/internal/textproc"
 	"github.com/redis/go-redis/v9"
 	"github.com/zelenin/go-tdlib/client"
 )
@@ -13,11 +18,17 @@ type ChannelState struct {
 }
 
 type AppState struct {
-	TlClient    *client.Client
-	Listener    *client.Listener
-	RedisClient *redis.Client
-	Channels    *ChannelState
-	S3Client    *s3.S3
+	TlClient      *client.Client
+	Listener      *client.Listener
+	RedisClient   *redis.Client
+	Channels      *ChannelState
+	S3Client      *s3.S3
+	Subscriptions *subscription.Store
+	Media         *MediaUploader
+	Text          *textproc.Pipeline
+	Chats         *chats.Cache
+	Jobs          *jobs.Manager
+	Sink          sink.Sink
 }
 
 func (s *AppState) Close() {
@@ -0,0 +1,86 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cursor is only moved forward, since batches may contain
+// older messages, e.g. edits or fetched history
+var advanceScript = redis.NewScript(`
+for i = 1, #ARGV, 2 do
+	local current = tonumber(redis.call('HGET', KEYS[1], ARGV[i]))
+	if not current or current < tonumber(ARGV[i + 1]) then
+		redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+	end
+end
+return 0
+`)
+
+// Cursors keeps the id of the last persisted message of every chat in a Redis
+// hash (chat id -> message id), so that the gap left by downtime can be found.
+type Cursors struct {
+	rdb *redis.Client
+	key string
+}
+
+func NewCursors(rdb *redis.Client, key string) *Cursors {
+	return &Cursors{
+		rdb: rdb,
+		key: key,
+	}
+}
+
+// Advance moves cursors of the given chats (chat id -> message id) forward.
+func (c *Cursors) Advance(ctx context.Context, last map[int64]int64) error {
+	if len(last) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(last)*2)
+	for chatId, messageId := range last {
+		args = append(args, chatId, messageId)
+	}
+
+	if err := advanceScript.Run(ctx, c.rdb, []string{c.key}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to advance cursors: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns cursors of the given chats. Chats without persisted messages
+// are omitted.
+func (c *Cursors) Get(ctx context.Context, chatIds []int64) (map[int64]int64, error) {
+	if len(chatIds) == 0 {
+		return map[int64]int64{}, nil
+	}
+
+	fields := make([]string, len(chatIds))
+	for i, id := range chatIds {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+
+	values, err := c.rdb.HMGet(ctx, c.key, fields...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cursors: %w", err)
+	}
+
+	last := make(map[int64]int64, len(chatIds))
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		messageId, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		last[chatIds[i]] = messageId
+	}
+
+	return last, nil
+}
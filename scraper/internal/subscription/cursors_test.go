@@ -0,0 +1,76 @@
+package subscription
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCursors(t *testing.T) *Cursors {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewCursors(rdb, "test:cursors")
+}
+
+func TestCursorsAdvance(t *testing.T) {
+	ctx := context.Background()
+	cursors := newTestCursors(t)
+
+	steps := []struct {
+		name    string
+		advance map[int64]int64
+		want    map[int64]int64
+	}{
+		{
+			name:    "initial",
+			advance: map[int64]int64{-1001: 5 << 20, -1002: 7 << 20},
+			want:    map[int64]int64{-1001: 5 << 20, -1002: 7 << 20},
+		},
+		{
+			name:    "forward",
+			advance: map[int64]int64{-1001: 6 << 20},
+			want:    map[int64]int64{-1001: 6 << 20, -1002: 7 << 20},
+		},
+		{
+			name:    "backward is ignored",
+			advance: map[int64]int64{-1001: 2 << 20, -1002: 8 << 20},
+			want:    map[int64]int64{-1001: 6 << 20, -1002: 8 << 20},
+		},
+		{
+			name:    "empty",
+			advance: map[int64]int64{},
+			want:    map[int64]int64{-1001: 6 << 20, -1002: 8 << 20},
+		},
+	}
+
+	for _, step := range steps {
+		if err := cursors.Advance(ctx, step.advance); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		got, err := cursors.Get(ctx, []int64{-1001, -1002, -1003})
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if !maps.Equal(got, step.want) {
+			t.Errorf("%s: cursors = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestCursorsGetEmpty(t *testing.T) {
+	got, err := newTestCursors(t).Get(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Get() = %v, want empty", got)
+	}
+}
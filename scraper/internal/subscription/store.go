@@ -0,0 +1,162 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrNotFound = errors.New("subscription not found")
+
+type Subscription struct {
+	ChatFolderLink string    `json:"chat_folder_link"`
+	ChatIds        []int64   `json:"chat_ids"`
+	Usernames      []string  `json:"usernames"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Store keeps subscriptions in a Redis hash (folder link -> subscription)
+// and mirrors them in memory, so that the event handler can check incoming
+// messages without a round trip to Redis.
+type Store struct {
+	rdb *redis.Client
+	key string
+
+	mu    sync.RWMutex
+	subs  map[string]*Subscription
+	chats map[int64]int
+}
+
+func NewStore(ctx context.Context, rdb *redis.Client, key string) (*Store, error) {
+	s := &Store{
+		rdb:   rdb,
+		key:   key,
+		subs:  make(map[string]*Subscription),
+		chats: make(map[int64]int),
+	}
+
+	entries, err := rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	for link, data := range entries {
+		var sub Subscription
+		if err := json.Unmarshal([]byte(data), &sub); err != nil {
+			zap.L().Error(
+				"Skipping malformed subscription",
+				zap.String("link", link),
+				zap.Error(err),
+			)
+			continue
+		}
+		s.index(&sub)
+	}
+
+	zap.L().Info("Loaded subscriptions", zap.Int("count", len(s.subs)))
+
+	return s, nil
+}
+
+func (s *Store) Add(ctx context.Context, sub *Subscription) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rdb.HSet(ctx, s.key, sub.ChatFolderLink, data).Err(); err != nil {
+		return fmt.Errorf("failed to persist subscription: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unindex(sub.ChatFolderLink)
+	s.index(sub)
+
+	return nil
+}
+
+func (s *Store) Remove(ctx context.Context, link string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[link]; !ok {
+		return nil, ErrNotFound
+	}
+
+	if err := s.rdb.HDel(ctx, s.key, link).Err(); err != nil {
+		return nil, fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	return s.unindex(link), nil
+}
+
+func (s *Store) List() []*Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subs := make([]*Subscription, 0, len(s.subs))
+	for _, sub := range s.subs {
+		subs = append(subs, sub)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+	})
+
+	return subs
+}
+
+func (s *Store) Has(chatId int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.chats[chatId] > 0
+}
+
+// ChatIds returns ids of all chats that belong to at least one subscription.
+func (s *Store) ChatIds() []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]int64, 0, len(s.chats))
+	for id, refs := range s.chats {
+		if refs > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (s *Store) index(sub *Subscription) {
+	s.subs[sub.ChatFolderLink] = sub
+	for _, id := range sub.ChatIds {
+		s.chats[id]++
+	}
+}
+
+func (s *Store) unindex(link string) *Subscription {
+	sub, ok := s.subs[link]
+	if !ok {
+		return nil
+	}
+
+	delete(s.subs, link)
+	for _, id := range sub.ChatIds {
+		s.chats[id]--
+		if s.chats[id] <= 0 {
+			delete(s.chats, id)
+		}
+	}
+
+	return sub
+}
@@ -0,0 +1,142 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) (*Store, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	store, err := NewStore(context.Background(), rdb, "test:subscriptions")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, rdb
+}
+
+func TestStoreReload(t *testing.T) {
+	ctx := context.Background()
+	store, rdb := newTestStore(t)
+
+	if err := store.Add(ctx, &Subscription{ChatFolderLink: "a", ChatIds: []int64{-1001, -1002}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.HSet(ctx, "test:subscriptions", "broken", "{").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewStore(ctx, rdb, "test:subscriptions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int64{-1001, -1002} {
+		if !reloaded.Has(id) {
+			t.Errorf("Has(%d) = false after reload, want true", id)
+		}
+	}
+	if reloaded.Has(-1003) {
+		t.Error("Has(-1003) = true, want false")
+	}
+}
+
+func TestStoreAddSameLink(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	for range 2 {
+		if err := store.Add(ctx, &Subscription{ChatFolderLink: "a", ChatIds: []int64{-1001}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refs := store.chats[-1001]; refs != 1 {
+		t.Errorf("references of -1001 = %d, want 1", refs)
+	}
+
+	if err := store.Add(ctx, &Subscription{ChatFolderLink: "a", ChatIds: []int64{-1002}}); err != nil {
+		t.Fatal(err)
+	}
+	if store.Has(-1001) {
+		t.Error("Has(-1001) = true after folder no longer contains it, want false")
+	}
+	if !store.Has(-1002) {
+		t.Error("Has(-1002) = false, want true")
+	}
+}
+
+func TestStoreSharedChat(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	subs := []*Subscription{
+		{ChatFolderLink: "a", ChatIds: []int64{-1001, -1002}},
+		{ChatFolderLink: "b", ChatIds: []int64{-1002, -1003}},
+	}
+	for _, sub := range subs {
+		if err := store.Add(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if refs := store.chats[-1002]; refs != 2 {
+		t.Errorf("references of -1002 = %d, want 2", refs)
+	}
+	for _, id := range []int64{-1001, -1002, -1003} {
+		if !store.Has(id) {
+			t.Errorf("Has(%d) = false, want true", id)
+		}
+	}
+}
+
+func TestStoreRemoveOverlapping(t *testing.T) {
+	ctx := context.Background()
+	store, rdb := newTestStore(t)
+
+	subs := []*Subscription{
+		{ChatFolderLink: "a", ChatIds: []int64{-1001, -1002}},
+		{ChatFolderLink: "b", ChatIds: []int64{-1002, -1003}},
+	}
+	for _, sub := range subs {
+		if err := store.Add(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := store.Remove(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.ChatFolderLink != "a" {
+		t.Errorf("removed %s, want a", removed.ChatFolderLink)
+	}
+
+	tests := []struct {
+		chatId int64
+		want   bool
+	}{
+		{-1001, false},
+		{-1002, true},
+		{-1003, true},
+	}
+	for _, tt := range tests {
+		if got := store.Has(tt.chatId); got != tt.want {
+			t.Errorf("Has(%d) = %v, want %v", tt.chatId, got, tt.want)
+		}
+	}
+
+	if exists, err := rdb.HExists(ctx, "test:subscriptions", "a").Result(); err != nil || exists {
+		t.Errorf("removed subscription is still persisted: %v", err)
+	}
+	if _, err := store.Remove(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Remove() error = %v, want %v", err, ErrNotFound)
+	}
+}
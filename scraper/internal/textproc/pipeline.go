@@ -0,0 +1,104 @@
+package textproc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"unicode/utf16"
+
+	"github.com/nrydanov/inbrief/config"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+// Processor is a single stage of text processing. It returns transformed
+// text and false if the message should be dropped.
+type Processor interface {
+	Process(text string) (string, bool)
+}
+
+// Pipeline strips configured entities from formatted text and then runs
+// plain text through processors in order.
+type Pipeline struct {
+	stripEntities []string
+	processors    []Processor
+}
+
+func NewPipeline(stripEntities []string, processors ...Processor) *Pipeline {
+	return &Pipeline{
+		stripEntities: stripEntities,
+		processors:    processors,
+	}
+}
+
+func FromConfig(cfg config.TextConfig) (*Pipeline, error) {
+	processors := make([]Processor, 0)
+
+	if cfg.RemoveEmoji {
+		processors = append(processors, EmojiRemover{})
+	}
+
+	if cfg.NormalizeWhitespace {
+		processors = append(processors, WhitespaceNormalizer{})
+	}
+
+	if len(cfg.DropPatterns) > 0 {
+		patterns := make([]*regexp.Regexp, len(cfg.DropPatterns))
+		for i, pattern := range cfg.DropPatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid drop pattern %q: %w", pattern, err)
+			}
+			patterns[i] = re
+		}
+		processors = append(processors, RegexDropper{Patterns: patterns})
+	}
+
+	if cfg.MinLength > 0 || cfg.MaxLength > 0 {
+		processors = append(processors, LengthFilter{
+			Min: cfg.MinLength,
+			Max: cfg.MaxLength,
+		})
+	}
+
+	return NewPipeline(cfg.StripEntities, processors...), nil
+}
+
+func (p *Pipeline) Process(text *client.FormattedText) (string, bool) {
+	result := p.strip(text)
+
+	for _, processor := range p.processors {
+		var ok bool
+		result, ok = processor.Process(result)
+		if !ok {
+			return "", false
+		}
+	}
+
+	return result, true
+}
+
+func (p *Pipeline) strip(text *client.FormattedText) string {
+	entities := slices.Clone(text.Entities)
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Offset > entities[j].Offset
+	})
+
+	u16Text := utf16.Encode([]rune(text.Text))
+
+	for _, entity := range entities {
+		if slices.Contains(p.stripEntities, entity.Type.TextEntityTypeType()) {
+			if entity.Offset >= 0 &&
+				entity.Offset < int32(len(u16Text)) &&
+				entity.Offset+entity.Length <= int32(len(u16Text)) {
+
+				u16Text = append(
+					u16Text[:entity.Offset],
+					u16Text[entity.Offset+entity.Length:]...)
+			}
+		}
+	}
+
+	return string(utf16.Decode(u16Text))
+}
@@ -0,0 +1,76 @@
+package textproc
+
+import (
+	"testing"
+
+	"github.com/nrydanov/inbrief/config"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+func TestPipelineStrip(t *testing.T) {
+	pipeline := NewPipeline(
+		[]string{"textEntityTypeHashtag", "textEntityTypeUrl"},
+		WhitespaceNormalizer{},
+	)
+
+	// Entity offsets are in UTF-16 code units, so the emoji
+	// before the hashtag takes two of them
+	text := &client.FormattedText{
+		Text: "🔥 #новости Важно: https://t.me/x *жирный*",
+		Entities: []*client.TextEntity{
+			{Offset: 3, Length: 8, Type: &client.TextEntityTypeHashtag{}},
+			{Offset: 19, Length: 14, Type: &client.TextEntityTypeUrl{}},
+			{Offset: 34, Length: 8, Type: &client.TextEntityTypeBold{}},
+			// Out of range entities are ignored
+			{Offset: 100, Length: 5, Type: &client.TextEntityTypeHashtag{}},
+		},
+	}
+
+	got, ok := pipeline.Process(text)
+	if !ok {
+		t.Fatal("Process() dropped the message")
+	}
+	if want := "🔥 Важно: *жирный*"; got != want {
+		t.Errorf("Process() = %q, want %q", got, want)
+	}
+	if len(text.Entities) != 4 || text.Entities[0].Offset != 3 {
+		t.Error("Process() reorders entities of the message")
+	}
+}
+
+func TestPipelineDrop(t *testing.T) {
+	pipeline := NewPipeline(nil, LengthFilter{Min: 10}, EmojiRemover{})
+
+	if _, ok := pipeline.Process(&client.FormattedText{Text: "short"}); ok {
+		t.Error("Process() kept message rejected by processor")
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	_, err := FromConfig(config.TextConfig{DropPatterns: []string{"("}})
+	if err == nil {
+		t.Error("FromConfig() error = nil, want error for invalid pattern")
+	}
+
+	pipeline, err := FromConfig(config.TextConfig{
+		RemoveEmoji:         true,
+		NormalizeWhitespace: true,
+		DropPatterns:        []string{"(?i)erid"},
+		MinLength:           3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pipeline.processors) != 4 {
+		t.Errorf("got %d processors, want 4", len(pipeline.processors))
+	}
+
+	got, ok := pipeline.Process(&client.FormattedText{Text: "🔥  Hello   world"})
+	if !ok || got != "Hello world" {
+		t.Errorf("Process() = %q, %v, want %q, true", got, ok, "Hello world")
+	}
+	if _, ok := pipeline.Process(&client.FormattedText{Text: "Реклама ERID: 123"}); ok {
+		t.Error("Process() kept message matching drop pattern")
+	}
+}
@@ -0,0 +1,95 @@
+package textproc
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type WhitespaceNormalizer struct{}
+
+// Process collapses runs of spaces within lines and runs of empty lines, so
+// that gaps left by stripped entities don't leak into output.
+func (WhitespaceNormalizer) Process(text string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+
+	empty := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !empty && len(result) > 0 {
+				result = append(result, "")
+			}
+			empty = true
+			continue
+		}
+		empty = false
+		result = append(result, line)
+	}
+
+	return strings.TrimSpace(strings.Join(result, "\n")), true
+}
+
+type EmojiRemover struct{}
+
+func (EmojiRemover) Process(text string) (string, bool) {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, text), true
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols, dingbats
+		return true
+	case r >= 0x2300 && r <= 0x23FF, r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences
+		return true
+	case r == 0x200D || r == 0xFE0F || r == 0x20E3: // joiners and modifiers
+		return true
+	default:
+		return false
+	}
+}
+
+// LengthFilter drops messages shorter than Min or longer than Max runes.
+// Zero disables corresponding bound.
+type LengthFilter struct {
+	Min int
+	Max int
+}
+
+func (f LengthFilter) Process(text string) (string, bool) {
+	length := utf8.RuneCountInString(text)
+
+	if f.Min > 0 && length < f.Min {
+		return "", false
+	}
+	if f.Max > 0 && length > f.Max {
+		return "", false
+	}
+
+	return text, true
+}
+
+// RegexDropper drops messages matching any of the patterns, e.g. ads.
+type RegexDropper struct {
+	Patterns []*regexp.Regexp
+}
+
+func (d RegexDropper) Process(text string) (string, bool) {
+	for _, pattern := range d.Patterns {
+		if pattern.MatchString(text) {
+			return "", false
+		}
+	}
+
+	return text, true
+}
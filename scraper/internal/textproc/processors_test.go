@@ -0,0 +1,93 @@
+package textproc
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestProcessors(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor Processor
+		text      string
+		want      string
+		ok        bool
+	}{
+		{
+			name:      "whitespace within lines",
+			processor: WhitespaceNormalizer{},
+			text:      "  Новость   дня \t здесь  ",
+			want:      "Новость дня здесь",
+			ok:        true,
+		},
+		{
+			name:      "whitespace between paragraphs",
+			processor: WhitespaceNormalizer{},
+			text:      "\n\nFirst\n \n\n  \nSecond\n\n",
+			want:      "First\n\nSecond",
+			ok:        true,
+		},
+		{
+			name:      "emoji",
+			processor: EmojiRemover{},
+			text:      "Hot 🔥 news ☀️ 1️⃣ 👨‍👩‍👧",
+			want:      "Hot  news  1 ",
+			ok:        true,
+		},
+		{
+			name:      "emoji keeps text",
+			processor: EmojiRemover{},
+			text:      "Курс € — 100₽",
+			want:      "Курс € — 100₽",
+			ok:        true,
+		},
+		{
+			name:      "too short",
+			processor: LengthFilter{Min: 5},
+			text:      "Да",
+			ok:        false,
+		},
+		{
+			name:      "min counts runes",
+			processor: LengthFilter{Min: 5},
+			text:      "Привет",
+			want:      "Привет",
+			ok:        true,
+		},
+		{
+			name:      "too long",
+			processor: LengthFilter{Max: 3},
+			text:      "long",
+			ok:        false,
+		},
+		{
+			name:      "no bounds",
+			processor: LengthFilter{},
+			text:      "",
+			want:      "",
+			ok:        true,
+		},
+		{
+			name:      "dropped by pattern",
+			processor: RegexDropper{Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)#реклама`)}},
+			text:      "Скидки! #Реклама",
+			ok:        false,
+		},
+		{
+			name:      "kept by pattern",
+			processor: RegexDropper{Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)#реклама`)}},
+			text:      "Обычная новость",
+			want:      "Обычная новость",
+			ok:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.processor.Process(tt.text)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Process(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
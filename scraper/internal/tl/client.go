@@ -6,12 +6,15 @@ import (
 	"path/filepath"
 
 	"github.com/nrydanov/inbrief/config"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
 
 	"github.com/zelenin/go-tdlib/client"
 	"go.uber.org/zap"
 )
 
 func InitClient(ctx context.Context, cfg config.Config) *client.Client {
+	limiter.ReplaceGlobal(limiter.New(cfg.Telegram.RateLimit))
+
 	tdlibParameters := &client.SetTdlibParametersRequest{
 		UseTestDc:           false,
 		DatabaseDirectory:   filepath.Join(".tdlib", "database"),
@@ -0,0 +1,240 @@
+package tl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf16"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal"
+	"github.com/nrydanov/inbrief/internal/chats"
+	"github.com/nrydanov/inbrief/internal/textproc"
+
+	"github.com/zelenin/go-tdlib/client"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newMessage converts TDLib message to protobuf one. ok is false for content
+// types that aren't scraped and for messages dropped by text pipeline.
+func newMessage(
+	message *client.Message,
+	chat *chats.Info,
+	pipeline *textproc.Pipeline,
+) (*pb.Message, bool) {
+	text, mediaType, ok := extractText(message.Content)
+	if !ok {
+		return nil, false
+	}
+
+	processed, ok := pipeline.Process(text)
+	if !ok {
+		return nil, false
+	}
+
+	msg := &pb.Message{
+		OriginalText:    text.Text,
+		Entities:        extractEntities(text),
+		Text:            processed,
+		Ts:              timestamppb.New(time.Unix(int64(message.Date), 0)),
+		Link:            MessageLink(chat, message.Id),
+		MediaType:       mediaType,
+		ChatId:          message.ChatId,
+		MessageId:       message.Id,
+		ChannelUsername: chat.Username,
+		ChannelTitle:    chat.Title,
+		Sender:          extractSender(message),
+		ChangeType:      pb.ChangeType_CHANGE_TYPE_NEW,
+	}
+
+	if info := message.InteractionInfo; info != nil {
+		msg.ViewCount = info.ViewCount
+		msg.ForwardCount = info.ForwardCount
+		msg.Reactions, msg.ReactionCount = extractReactions(info.Reactions)
+	}
+
+	if reply, ok := message.ReplyTo.(*client.MessageReplyToMessage); ok {
+		msg.ReplyToMessageId = &reply.MessageId
+	}
+
+	if message.EditDate != 0 {
+		msg.EditedAt = timestamppb.New(time.Unix(int64(message.EditDate), 0))
+	}
+
+	return msg, true
+}
+
+var entityTypes = map[string]pb.EntityType{
+	"textEntityTypeUrl":         pb.EntityType_ENTITY_TYPE_URL,
+	"textEntityTypeTextUrl":     pb.EntityType_ENTITY_TYPE_TEXT_URL,
+	"textEntityTypeMention":     pb.EntityType_ENTITY_TYPE_MENTION,
+	"textEntityTypeMentionName": pb.EntityType_ENTITY_TYPE_MENTION_NAME,
+	"textEntityTypeHashtag":     pb.EntityType_ENTITY_TYPE_HASHTAG,
+	"textEntityTypeCashtag":     pb.EntityType_ENTITY_TYPE_CASHTAG,
+	"textEntityTypeBotCommand":  pb.EntityType_ENTITY_TYPE_BOT_COMMAND,
+}
+
+// extractEntities returns links, mentions and tags of the text ordered by
+// offset. Formatting entities (bold, italic, etc.) are ignored.
+func extractEntities(text *client.FormattedText) []*pb.TextEntity {
+	u16Text := utf16.Encode([]rune(text.Text))
+	entities := make([]*pb.TextEntity, 0, len(text.Entities))
+
+	for _, entity := range text.Entities {
+		entityType, ok := entityTypes[entity.Type.TextEntityTypeType()]
+		if !ok {
+			continue
+		}
+		if entity.Offset < 0 || entity.Offset+entity.Length > int32(len(u16Text)) {
+			continue
+		}
+
+		e := &pb.TextEntity{
+			Type:   entityType,
+			Offset: entity.Offset,
+			Length: entity.Length,
+			Value: string(utf16.Decode(
+				u16Text[entity.Offset : entity.Offset+entity.Length],
+			)),
+		}
+
+		switch t := entity.Type.(type) {
+		case *client.TextEntityTypeTextUrl:
+			e.Url = &t.Url
+		case *client.TextEntityTypeMentionName:
+			e.UserId = &t.UserId
+		}
+
+		entities = append(entities, e)
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Offset < entities[j].Offset
+	})
+
+	return entities
+}
+
+func extractSender(message *client.Message) *pb.Sender {
+	sender := &pb.Sender{
+		AuthorSignature: message.AuthorSignature,
+	}
+
+	switch s := message.SenderId.(type) {
+	case *client.MessageSenderUser:
+		sender.Id = &pb.Sender_UserId{UserId: s.UserId}
+	case *client.MessageSenderChat:
+		sender.Id = &pb.Sender_ChatId{ChatId: s.ChatId}
+	}
+
+	return sender
+}
+
+func extractReactions(reactions *client.MessageReactions) (map[string]int32, int32) {
+	if reactions == nil {
+		return nil, 0
+	}
+
+	counts := make(map[string]int32, len(reactions.Reactions))
+	total := int32(0)
+	for _, reaction := range reactions.Reactions {
+		var key string
+		switch r := reaction.Type.(type) {
+		case *client.ReactionTypeEmoji:
+			key = r.Emoji
+		case *client.ReactionTypeCustomEmoji:
+			key = fmt.Sprintf("custom:%d", r.CustomEmojiId)
+		case *client.ReactionTypePaid:
+			key = "paid"
+		default:
+			continue
+		}
+
+		counts[key] += reaction.TotalCount
+		total += reaction.TotalCount
+	}
+
+	return counts, total
+}
+
+// extractText returns message text (or media caption) along with the media
+// type. ok is false for content types that aren't scraped.
+func extractText(
+	content client.MessageContent,
+) (*client.FormattedText, pb.MediaType, bool) {
+	var text *client.FormattedText
+	var mediaType pb.MediaType
+
+	switch c := content.(type) {
+	case *client.MessageText:
+		text, mediaType = c.Text, pb.MediaType_MEDIA_TYPE_TEXT
+	case *client.MessagePhoto:
+		text, mediaType = c.Caption, pb.MediaType_MEDIA_TYPE_PHOTO
+	case *client.MessageVideo:
+		text, mediaType = c.Caption, pb.MediaType_MEDIA_TYPE_VIDEO
+	case *client.MessageDocument:
+		text, mediaType = c.Caption, pb.MediaType_MEDIA_TYPE_DOCUMENT
+	case *client.MessageAnimation:
+		text, mediaType = c.Caption, pb.MediaType_MEDIA_TYPE_ANIMATION
+	case *client.MessageAudio:
+		text, mediaType = c.Caption, pb.MediaType_MEDIA_TYPE_AUDIO
+	default:
+		return nil, pb.MediaType_MEDIA_TYPE_UNSPECIFIED, false
+	}
+
+	if text == nil {
+		text = &client.FormattedText{}
+	}
+
+	return text, mediaType, true
+}
+
+// mediaFile returns photo or document of the message, if any.
+func mediaFile(message *client.Message) (*client.File, string, bool) {
+	switch c := message.Content.(type) {
+	case *client.MessagePhoto:
+		if len(c.Photo.Sizes) == 0 {
+			return nil, "", false
+		}
+		// Sizes are sorted in increasing order
+		return c.Photo.Sizes[len(c.Photo.Sizes)-1].Photo, "image/jpeg", true
+	case *client.MessageDocument:
+		return c.Document.Document, c.Document.MimeType, true
+	default:
+		return nil, "", false
+	}
+}
+
+// attachMedia uploads photo or document of the message (if any) and
+// references it from out. Upload errors don't prevent message from being
+// scraped.
+func attachMedia(
+	ctx context.Context,
+	media *internal.MediaUploader,
+	message *client.Message,
+	out *pb.Message,
+) {
+	if media == nil {
+		return
+	}
+
+	file, mimeType, ok := mediaFile(message)
+	if !ok {
+		return
+	}
+
+	key, err := media.Upload(ctx, file, mimeType, message.ChatId, message.Id)
+	if errors.Is(err, internal.ErrMediaSkipped) {
+		return
+	}
+	if err != nil {
+		zap.L().Error("Unable to upload media", zap.Error(err))
+		return
+	}
+
+	out.MediaKey = &key
+	out.MediaMimeType = &mimeType
+}
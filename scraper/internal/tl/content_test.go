@@ -0,0 +1,249 @@
+package tl
+
+import (
+	"maps"
+	"testing"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+
+	"github.com/zelenin/go-tdlib/client"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractText(t *testing.T) {
+	caption := &client.FormattedText{Text: "Подпись к фото"}
+
+	tests := []struct {
+		name      string
+		content   client.MessageContent
+		text      string
+		mediaType pb.MediaType
+		ok        bool
+	}{
+		{
+			name:      "text",
+			content:   &client.MessageText{Text: &client.FormattedText{Text: "Новость"}},
+			text:      "Новость",
+			mediaType: pb.MediaType_MEDIA_TYPE_TEXT,
+			ok:        true,
+		},
+		{
+			name:      "photo caption without entities",
+			content:   &client.MessagePhoto{Caption: caption},
+			text:      caption.Text,
+			mediaType: pb.MediaType_MEDIA_TYPE_PHOTO,
+			ok:        true,
+		},
+		{
+			name:      "video without caption",
+			content:   &client.MessageVideo{},
+			mediaType: pb.MediaType_MEDIA_TYPE_VIDEO,
+			ok:        true,
+		},
+		{
+			name:      "document",
+			content:   &client.MessageDocument{Caption: caption},
+			text:      caption.Text,
+			mediaType: pb.MediaType_MEDIA_TYPE_DOCUMENT,
+			ok:        true,
+		},
+		{
+			name:      "animation",
+			content:   &client.MessageAnimation{Caption: caption},
+			text:      caption.Text,
+			mediaType: pb.MediaType_MEDIA_TYPE_ANIMATION,
+			ok:        true,
+		},
+		{
+			name:      "audio",
+			content:   &client.MessageAudio{Caption: caption},
+			text:      caption.Text,
+			mediaType: pb.MediaType_MEDIA_TYPE_AUDIO,
+			ok:        true,
+		},
+		{
+			name:      "sticker",
+			content:   &client.MessageSticker{},
+			mediaType: pb.MediaType_MEDIA_TYPE_UNSPECIFIED,
+			ok:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, mediaType, ok := extractText(tt.content)
+			if ok != tt.ok || mediaType != tt.mediaType {
+				t.Fatalf("extractText() = %v, %v, want %v, %v", mediaType, ok, tt.mediaType, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if text == nil || text.Text != tt.text {
+				t.Errorf("text = %v, want %q", text, tt.text)
+			}
+		})
+	}
+}
+
+func TestExtractSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *client.Message
+		want    *pb.Sender
+	}{
+		{
+			name:    "user",
+			message: &client.Message{SenderId: &client.MessageSenderUser{UserId: 42}},
+			want:    &pb.Sender{Id: &pb.Sender_UserId{UserId: 42}},
+		},
+		{
+			name: "channel with signature",
+			message: &client.Message{
+				SenderId:        &client.MessageSenderChat{ChatId: -1001},
+				AuthorSignature: "Редакция",
+			},
+			want: &pb.Sender{
+				Id:              &pb.Sender_ChatId{ChatId: -1001},
+				AuthorSignature: "Редакция",
+			},
+		},
+		{
+			name:    "unknown",
+			message: &client.Message{},
+			want:    &pb.Sender{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractSender(tt.message); !proto.Equal(got, tt.want) {
+				t.Errorf("extractSender() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractReactions(t *testing.T) {
+	reaction := func(reactionType client.ReactionType, count int32) *client.MessageReaction {
+		return &client.MessageReaction{Type: reactionType, TotalCount: count}
+	}
+
+	tests := []struct {
+		name      string
+		reactions *client.MessageReactions
+		want      map[string]int32
+		total     int32
+	}{
+		{
+			name:      "none",
+			reactions: nil,
+			want:      nil,
+			total:     0,
+		},
+		{
+			name: "emoji",
+			reactions: &client.MessageReactions{Reactions: []*client.MessageReaction{
+				reaction(&client.ReactionTypeEmoji{Emoji: "👍"}, 5),
+				reaction(&client.ReactionTypeEmoji{Emoji: "🔥"}, 2),
+			}},
+			want:  map[string]int32{"👍": 5, "🔥": 2},
+			total: 7,
+		},
+		{
+			name: "custom and paid",
+			reactions: &client.MessageReactions{Reactions: []*client.MessageReaction{
+				reaction(&client.ReactionTypeCustomEmoji{CustomEmojiId: 5368324170671202286}, 3),
+				reaction(&client.ReactionTypePaid{}, 10),
+				reaction(&client.ReactionTypeEmoji{Emoji: "👍"}, 1),
+			}},
+			want:  map[string]int32{"custom:5368324170671202286": 3, "paid": 10, "👍": 1},
+			total: 14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := extractReactions(tt.reactions)
+			if !maps.Equal(got, tt.want) || total != tt.total {
+				t.Errorf("extractReactions() = %v, %d, want %v, %d", got, total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestExtractEntities(t *testing.T) {
+	entity := func(offset, length int32, entityType client.TextEntityType) *client.TextEntity {
+		return &client.TextEntity{Offset: offset, Length: length, Type: entityType}
+	}
+	url := "https://inbrief.app"
+	userId := int64(42)
+
+	tests := []struct {
+		name string
+		text *client.FormattedText
+		want []*pb.TextEntity
+	}{
+		{
+			name: "no entities",
+			text: &client.FormattedText{Text: "Подпись к фото"},
+			want: []*pb.TextEntity{},
+		},
+		{
+			name: "utf-16 offsets after emoji",
+			text: &client.FormattedText{
+				Text: "🔥 #новости https://t.me/x",
+				Entities: []*client.TextEntity{
+					entity(12, 14, &client.TextEntityTypeUrl{}),
+					entity(3, 8, &client.TextEntityTypeHashtag{}),
+				},
+			},
+			want: []*pb.TextEntity{
+				{Type: pb.EntityType_ENTITY_TYPE_HASHTAG, Offset: 3, Length: 8, Value: "#новости"},
+				{Type: pb.EntityType_ENTITY_TYPE_URL, Offset: 12, Length: 14, Value: "https://t.me/x"},
+			},
+		},
+		{
+			name: "text url and mention name",
+			text: &client.FormattedText{
+				Text: "Читать у автора",
+				Entities: []*client.TextEntity{
+					entity(0, 6, &client.TextEntityTypeTextUrl{Url: url}),
+					entity(9, 6, &client.TextEntityTypeMentionName{UserId: userId}),
+				},
+			},
+			want: []*pb.TextEntity{
+				{Type: pb.EntityType_ENTITY_TYPE_TEXT_URL, Offset: 0, Length: 6, Value: "Читать", Url: &url},
+				{Type: pb.EntityType_ENTITY_TYPE_MENTION_NAME, Offset: 9, Length: 6, Value: "автора", UserId: &userId},
+			},
+		},
+		{
+			name: "formatting and out of range",
+			text: &client.FormattedText{
+				Text: "@inbrief",
+				Entities: []*client.TextEntity{
+					entity(0, 8, &client.TextEntityTypeBold{}),
+					entity(0, 8, &client.TextEntityTypeMention{}),
+					entity(4, 10, &client.TextEntityTypeUrl{}),
+					entity(-1, 2, &client.TextEntityTypeHashtag{}),
+				},
+			},
+			want: []*pb.TextEntity{
+				{Type: pb.EntityType_ENTITY_TYPE_MENTION, Offset: 0, Length: 8, Value: "@inbrief"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractEntities(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("extractEntities() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("entity %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -1,18 +1,21 @@
 package tl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
 	"github.com/nrydanov/inbrief/internal"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
 
 	"github.com/zelenin/go-tdlib/client"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func FetchChannel(
+	ctx context.Context,
 	chId int64,
 	leftBound time.Time,
 	rightBound time.Time,
@@ -20,44 +23,97 @@ func FetchChannel(
 ) ([]*pb.Message, error) {
 	messages := make([]*pb.Message, 0)
 
-	// TODO(nrydanov): Add right bound support
-	fromMessageId := int64(0)
-	for {
-		history, err := state.TlClient.GetChatHistory(
-			&client.GetChatHistoryRequest{
-				ChatId:        int64(chId),
-				FromMessageId: fromMessageId,
-				Limit:         100,
-			},
-		)
-		if err != nil {
-			zap.L().Debug("Unable to get chat history")
-			return nil, err
-		}
+	err := FetchChannelPages(
+		ctx,
+		chId,
+		0,
+		leftBound,
+		rightBound,
+		state,
+		func(page []*pb.Message) error {
+			messages = append(messages, page...)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		chat, err := state.TlClient.GetChat(&client.GetChatRequest{
-			ChatId: chId,
-		})
+	return messages, nil
+}
 
+// FetchChannelPages works like FetchChannel, but hands messages over to emit
+// page by page instead of accumulating the whole history in memory. Non-zero
+// after resumes pagination from messages older than the given one, e.g. the
+// last message of a previously emitted page.
+func FetchChannelPages(
+	ctx context.Context,
+	chId int64,
+	after int64,
+	leftBound time.Time,
+	rightBound time.Time,
+	state *internal.AppState,
+	emit func([]*pb.Message) error,
+) error {
+	var fromMessageId int64
+	var offset int32
+	var bounded bool
+	if after != 0 {
+		// Everything older than already emitted message is
+		// within right bound
+		fromMessageId = after
+	} else {
+		var err error
+		fromMessageId, offset, err = findStartMessage(ctx, state.TlClient, chId, rightBound)
+		if errors.Is(err, errNoMessages) {
+			zap.L().Debug("No messages before right bound")
+			return nil
+		}
 		if err != nil {
-			zap.L().Debug("Unable to get chat")
-			return nil, err
+			return err
 		}
+		bounded = fromMessageId != 0
+	}
+
+	chat, err := state.Chats.Get(ctx, chId)
+	if err != nil {
+		zap.L().Debug("Unable to resolve chat", zap.Error(err))
+		return err
+	}
 
-		username, err := ExtractUsername(state.TlClient, chat)
+	zap.L().Debug("Chat info",
+		zap.Any("chat", chat),
+		zap.String("type", chat.Type.ChatTypeType()),
+	)
+
+	for {
+		history, err := limiter.Call(ctx, func() (*client.Messages, error) {
+			return state.TlClient.GetChatHistory(
+				&client.GetChatHistoryRequest{
+					ChatId:        int64(chId),
+					FromMessageId: fromMessageId,
+					Offset:        offset,
+					Limit:         100,
+				},
+			)
+		})
 		if err != nil {
-			zap.L().Debug("Unable to extract username", zap.Error(err))
-			continue
+			zap.L().Debug("Unable to get chat history")
+			return err
 		}
 
-		zap.L().Debug("Chat info",
-			zap.Any("chat", chat),
-			zap.String("type", chat.Type.ChatTypeType()),
-		)
+		if len(history.Messages) == 0 {
+			zap.L().Debug("Reached beginning of chat history")
+			break
+		}
 
 		reachedEnd := false
+		messages := make([]*pb.Message, 0, len(history.Messages))
 
 		for _, message := range history.Messages {
+			if bounded && int64(message.Date) > rightBound.Unix() {
+				continue
+			}
 			if int64(message.Date) < leftBound.Unix() {
 				zap.L().Debug("Reached left bound")
 				reachedEnd = true
@@ -68,24 +124,64 @@ func FetchChannel(
 				zap.String("time", fmt.Sprintf("%+v", message.Date)),
 			)
 
-			switch message.Content.(type) {
-			case *client.MessageText:
-				messages = append(messages, &pb.Message{
-					Text: processText(message.Content.(*client.MessageText).Text),
-					Ts:   timestamppb.New(time.Unix(int64(message.Date), 0)),
-					Link: fmt.Sprintf("https://t.me/%s/%d", username, message.Id),
-				})
-			default:
+			msg, ok := newMessage(message, chat, state.Text)
+			if !ok {
 				continue
 			}
+			attachMedia(ctx, state.Media, message, msg)
+
+			messages = append(messages, msg)
 		}
+
+		if len(messages) > 0 {
+			if err := emit(messages); err != nil {
+				return err
+			}
+		}
+
 		if reachedEnd {
 			break
 		}
 
 		fromMessageId = history.Messages[len(history.Messages)-1].Id
+		offset = 0
 
 	}
 
-	return messages, nil
+	return nil
+}
+
+var errNoMessages = errors.New("no messages found")
+
+// findStartMessage returns the message pagination should start from, so that
+// history newer than rightBound is never requested. Zero or future right
+// bound means "start from the last message".
+func findStartMessage(
+	ctx context.Context,
+	c *client.Client,
+	chId int64,
+	rightBound time.Time,
+) (int64, int32, error) {
+	if rightBound.Unix() <= 0 || rightBound.After(time.Now()) {
+		return 0, 0, nil
+	}
+
+	message, err := limiter.Call(ctx, func() (*client.Message, error) {
+		return c.GetChatMessageByDate(&client.GetChatMessageByDateRequest{
+			ChatId: chId,
+			Date:   int32(rightBound.Unix()),
+		})
+	})
+	var respErr client.ResponseError
+	if errors.As(err, &respErr) && respErr.Err.Code == 404 {
+		return 0, 0, errNoMessages
+	}
+	if err != nil {
+		zap.L().Debug("Unable to get message by date", zap.Error(err))
+		return 0, 0, err
+	}
+
+	// History is fetched from messages older than
+	// from_message_id, so negative offset is needed to include message itself
+	return message.Id, -1, nil
 }
@@ -0,0 +1,202 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nrydanov/inbrief/config"
+
+	"github.com/zelenin/go-tdlib/client"
+	"go.uber.org/zap"
+)
+
+var floodWaitRe = regexp.MustCompile(`(?:FLOOD_WAIT_|retry after )(\d+)`)
+
+// Limiter throttles TDLib requests with a token bucket and retries the ones
+// that failed with FLOOD_WAIT or transient errors.
+type Limiter struct {
+	rate         float64
+	burst        float64
+	maxRetries   int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	maxFloodWait time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// FLOOD_WAIT applies to the whole account, so the rest of
+	// requests are paused too
+	pausedUntil time.Time
+}
+
+func New(cfg config.RateLimitConfig) *Limiter {
+	return &Limiter{
+		rate:         cfg.Rps,
+		burst:        float64(max(cfg.Burst, 1)),
+		maxRetries:   cfg.MaxRetries,
+		backoff:      cfg.Backoff,
+		maxBackoff:   cfg.MaxBackoff,
+		maxFloodWait: cfg.MaxFloodWait,
+		tokens:       float64(max(cfg.Burst, 1)),
+		last:         time.Now(),
+	}
+}
+
+var (
+	globalMu sync.RWMutex
+	global   = New(config.RateLimitConfig{
+		Rps:          20,
+		Burst:        20,
+		MaxRetries:   5,
+		Backoff:      500 * time.Millisecond,
+		MaxBackoff:   30 * time.Second,
+		MaxFloodWait: 5 * time.Minute,
+	})
+)
+
+func L() *Limiter {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+
+	return global
+}
+
+func ReplaceGlobal(l *Limiter) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	global = l
+}
+
+// Call runs TDLib request through the global limiter.
+func Call[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	return Do(ctx, L(), call)
+}
+
+func Do[T any](ctx context.Context, l *Limiter, call func() (T, error)) (T, error) {
+	backoff := l.backoff
+
+	for attempt := 0; ; attempt++ {
+		if err := sleep(ctx, l.reserve()); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err := call()
+		if err == nil || attempt >= l.maxRetries {
+			return result, err
+		}
+
+		if wait, ok := floodWait(err); ok {
+			if wait > l.maxFloodWait {
+				return result, fmt.Errorf("flood wait of %s exceeds limit: %w", wait, err)
+			}
+			zap.L().Warn("Hit flood wait, retrying", zap.Duration("wait", wait))
+			l.pause(wait)
+			continue
+		}
+
+		if !transient(err) {
+			return result, err
+		}
+
+		zap.L().Debug(
+			"Transient TDLib error, retrying",
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		if err := sleep(ctx, backoff); err != nil {
+			return result, err
+		}
+		backoff = min(backoff*2, l.maxBackoff)
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before
+// the request may be sent. Waiting itself is done without holding the lock.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	start := now
+	if start.Before(l.pausedUntil) {
+		start = l.pausedUntil
+	}
+
+	// Tokens may go negative, which means that requests are
+	// already queued and the next one is scheduled after them
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+
+	if l.tokens < 0 {
+		start = start.Add(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	}
+
+	return start.Sub(now)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *Limiter) pause(d time.Duration) {
+	l.mu.Lock()
+	until := time.Now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.mu.Unlock()
+}
+
+func floodWait(err error) (time.Duration, bool) {
+	var respErr client.ResponseError
+	if !errors.As(err, &respErr) {
+		return 0, false
+	}
+	if respErr.Err.Code != 429 && respErr.Err.Code != 420 {
+		return 0, false
+	}
+
+	match := floodWaitRe.FindStringSubmatch(respErr.Err.Message)
+	if match == nil {
+		return time.Second, true
+	}
+
+	seconds, err := strconv.Atoi(match[1])
+	if err != nil {
+		return time.Second, true
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// transient reports whether request may succeed on retry. Errors that don't
+// come from TDLib itself (e.g. response timeouts) are considered transient.
+func transient(err error) bool {
+	var respErr client.ResponseError
+	if !errors.As(err, &respErr) {
+		return true
+	}
+
+	return respErr.Err.Code >= 500
+}
@@ -0,0 +1,105 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nrydanov/inbrief/config"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+func responseError(code int32, message string) error {
+	return client.ResponseError{Err: &client.Error{Code: code, Message: message}}
+}
+
+func TestFloodWait(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+		ok   bool
+	}{
+		{"flood wait", responseError(429, "FLOOD_WAIT_15"), 15 * time.Second, true},
+		{"retry after", responseError(429, "Too Many Requests: retry after 7"), 7 * time.Second, true},
+		{"code 420", responseError(420, "FLOOD_WAIT_3"), 3 * time.Second, true},
+		{"no duration", responseError(429, "Too Many Requests"), time.Second, true},
+		{"other code", responseError(400, "FLOOD_WAIT_15"), 0, false},
+		{"not tdlib", errors.New("FLOOD_WAIT_15"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := floodWait(tt.err)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("floodWait() = %s, %v, want %s, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not tdlib", errors.New("timeout"), true},
+		{"internal", responseError(500, "Internal Server Error"), true},
+		{"unavailable", responseError(503, "Service Unavailable"), true},
+		{"bad request", responseError(400, "CHANNEL_PRIVATE"), false},
+		{"not found", responseError(404, "Not Found"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.err); got != tt.want {
+				t.Errorf("transient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoCancelled(t *testing.T) {
+	l := New(config.RateLimitConfig{Rps: 1, Burst: 1})
+	l.pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	called := false
+	_, err := Do(ctx, l, func() (int, error) {
+		called = true
+		return 0, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if called {
+		t.Error("request is sent after context is done")
+	}
+}
+
+func TestDoRetriesTransient(t *testing.T) {
+	l := New(config.RateLimitConfig{
+		Rps:        1000,
+		Burst:      10,
+		MaxRetries: 2,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	})
+
+	calls := 0
+	_, err := Do(context.Background(), l, func() (int, error) {
+		calls++
+		return 0, responseError(500, "Internal Server Error")
+	})
+	if err == nil {
+		t.Fatal("Do() error = nil, want last error")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
@@ -0,0 +1,113 @@
+package tl
+
+import (
+	"context"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
+
+	"github.com/zelenin/go-tdlib/client"
+	"go.uber.org/zap"
+)
+
+// recoverGaps backfills messages of subscribed chats that were posted after
+// the last persisted one, i.e. while the scraper was down.
+func (eh *EventHandler) recoverGaps(ctx context.Context) {
+	if eh.cursors == nil || eh.subs == nil {
+		return
+	}
+
+	chatIds := eh.subs.ChatIds()
+	last, err := eh.cursors.Get(ctx, chatIds)
+	if err != nil {
+		zap.L().Error("Unable to load cursors, skipping gap recovery", zap.Error(err))
+		return
+	}
+
+	total := 0
+	for _, chatId := range chatIds {
+		if ctx.Err() != nil {
+			return
+		}
+
+		after, ok := last[chatId]
+		if !ok {
+			zap.L().Debug("No persisted messages, skipping chat", zap.Int64("chat_id", chatId))
+			continue
+		}
+
+		count, err := eh.recoverChat(ctx, chatId, after)
+		if err != nil {
+			zap.L().Error(
+				"Unable to recover chat",
+				zap.Int64("chat_id", chatId),
+				zap.Error(err),
+			)
+		}
+		total += count
+	}
+
+	zap.L().Info(
+		"Recovered messages missed during downtime",
+		zap.Int("chats", len(chatIds)),
+		zap.Int("count", total),
+	)
+}
+
+func (eh *EventHandler) recoverChat(
+	ctx context.Context,
+	chatId int64,
+	after int64,
+) (int, error) {
+	missed := make([]*client.Message, 0)
+	var fromMessageId int64
+
+	for {
+		history, err := limiter.Call(ctx, func() (*client.Messages, error) {
+			return eh.client.GetChatHistory(&client.GetChatHistoryRequest{
+				ChatId:        chatId,
+				FromMessageId: fromMessageId,
+				Limit:         100,
+			})
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if len(history.Messages) == 0 {
+			break
+		}
+
+		reached := false
+		for _, message := range history.Messages {
+			if message.Id <= after {
+				reached = true
+				break
+			}
+			missed = append(missed, message)
+		}
+
+		if reached {
+			break
+		}
+		if eh.maxGap > 0 && len(missed) >= eh.maxGap {
+			zap.L().Warn(
+				"Gap exceeds limit, oldest messages are skipped",
+				zap.Int64("chat_id", chatId),
+				zap.Int("limit", eh.maxGap),
+			)
+			missed = missed[:eh.maxGap]
+			break
+		}
+
+		fromMessageId = history.Messages[len(history.Messages)-1].Id
+	}
+
+	for i := len(missed) - 1; i >= 0; i-- {
+		if err := eh.forward(ctx, missed[i], pb.ChangeType_CHANGE_TYPE_NEW); err != nil {
+			return len(missed) - 1 - i, err
+		}
+	}
+
+	return len(missed), nil
+}
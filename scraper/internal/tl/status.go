@@ -0,0 +1,65 @@
+package tl
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/chats"
+
+	"github.com/zelenin/go-tdlib/client"
+	"go.uber.org/zap"
+)
+
+// ChannelStatus describes the outcome of scraping a single chat, so that
+// callers can tell an empty channel apart from an inaccessible one.
+func ChannelStatus(
+	ctx context.Context,
+	cache *chats.Cache,
+	chId int64,
+	count int,
+	fetchErr error,
+) *pb.ChannelStatus {
+	status := &pb.ChannelStatus{
+		ChatId:       chId,
+		MessageCount: int32(count),
+	}
+
+	info, err := cache.Get(ctx, chId)
+	if err != nil {
+		zap.L().Debug("Unable to resolve username", zap.Error(err))
+	} else {
+		status.Username = info.Username
+	}
+
+	if fetchErr != nil {
+		code := ErrorCode(fetchErr)
+		message := fetchErr.Error()
+		status.ErrorCode = &code
+		status.ErrorMessage = &message
+	}
+
+	return status
+}
+
+func ErrorCode(err error) pb.FetchErrorCode {
+	if errors.Is(err, ErrUnsupportedChat) {
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_UNSUPPORTED
+	}
+
+	var respErr client.ResponseError
+	if !errors.As(err, &respErr) {
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_INTERNAL
+	}
+
+	switch respErr.Err.Code {
+	case 400, 401, 403:
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_INACCESSIBLE
+	case 404:
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_NOT_FOUND
+	case 429:
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_RATE_LIMITED
+	default:
+		return pb.FetchErrorCode_FETCH_ERROR_CODE_INTERNAL
+	}
+}
@@ -3,33 +3,57 @@ package tl
 import (
 	"context"
 	"fmt"
-	"slices"
-	"sort"
-	"time"
-	"unicode/utf16"
 
 	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal"
+	"github.com/nrydanov/inbrief/internal/chats"
+	"github.com/nrydanov/inbrief/internal/subscription"
+	"github.com/nrydanov/inbrief/internal/textproc"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
 	"github.com/redis/go-redis/v9"
 	"github.com/zelenin/go-tdlib/client"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type EventHandler struct {
-	listener *client.Listener
-	outputCh chan<- *pb.Message
-	client   *client.Client
+	listener   *client.Listener
+	outputCh   chan<- *pb.Message
+	client     *client.Client
+	subs       *subscription.Store
+	media      *internal.MediaUploader
+	text       *textproc.Pipeline
+	chats      *chats.Cache
+	cursors    *subscription.Cursors
+	maxGap     int
+	maxPending int
 }
 
 func NewEventHandler(
 	outputCh chan<- *pb.Message,
 	client *client.Client,
+	subs *subscription.Store,
+	media *internal.MediaUploader,
+	text *textproc.Pipeline,
+	chats *chats.Cache,
+	cursors *subscription.Cursors,
+	maxGap int,
+	maxPending int,
 	bufferSize int,
 ) *EventHandler {
 	return &EventHandler{
 		client:   client,
 		listener: client.GetListener(),
 		outputCh: outputCh,
+		subs:     subs,
+		media:    media,
+		text:     text,
+		chats:    chats,
+		cursors:  cursors,
+		maxGap:   maxGap,
+
+		maxPending: maxPending,
 	}
 }
 
@@ -38,57 +62,113 @@ func (eh *EventHandler) Handle(
 	listener *client.Listener,
 	rdb *redis.Client,
 ) {
+	// Message updates are held back until the gap left by
+	// downtime is backfilled, so that live messages are written after
+	// recovered ones. Listener is drained meanwhile, otherwise TDLib would
+	// block responses needed for recovery as well
+	recovered := make(chan struct{})
+	go func() {
+		defer close(recovered)
+		eh.recoverGaps(ctx)
+	}()
+	pending := make([]client.Type, 0)
+	replay := func() {
+		zap.L().Debug("Replaying held updates", zap.Int("count", len(pending)))
+		for _, update := range pending {
+			eh.handleUpdate(ctx, update)
+		}
+		pending = nil
+	}
+
 	for {
 		select {
 		case update := <-listener.Updates:
-			switch msg := update.(type) {
-			case *client.UpdateNewMessage:
-				err := eh.newMessageHandler(msg)
-				if err != nil {
-					zap.L().Error("Unable to handle new message", zap.Error(err))
-				}
+			if pending == nil || !eh.subscribedMessage(update) {
+				eh.handleUpdate(ctx, update)
 				continue
 			}
+
+			pending = append(pending, update)
+			if eh.maxPending > 0 && len(pending) >= eh.maxPending {
+				zap.L().Warn(
+					"Too many updates during recovery, live messages may be written before recovered ones",
+					zap.Int("limit", eh.maxPending),
+				)
+				replay()
+			}
+		case <-recovered:
+			replay()
+			recovered = nil
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func processText(text *client.FormattedText) string {
-	blacklist := []string{
-		"textEntityTypeBotCommand",
-		"textEntityTypeHashtag",
-		"textEntityTypeMention",
-		"textEntityTypeCashtag",
-		"textEntityTypeMentionName",
-		"textEntityTypeUrl",
-		"textEntityTypeTextUrl",
+// subscribedMessage reports whether update changes a message of subscribed
+// chat. Updates of other chats are dropped anyway, so they aren't held back.
+func (eh *EventHandler) subscribedMessage(update client.Type) bool {
+	var chatId int64
+	switch msg := update.(type) {
+	case *client.UpdateNewMessage:
+		chatId = msg.Message.ChatId
+	case *client.UpdateMessageContent:
+		chatId = msg.ChatId
+	case *client.UpdateMessageEdited:
+		chatId = msg.ChatId
+	case *client.UpdateDeleteMessages:
+		chatId = msg.ChatId
+	default:
+		return false
 	}
 
-	sort.Slice(text.Entities, func(i, j int) bool {
-		return text.Entities[i].Offset > text.Entities[j].Offset
-	})
-
-	u16Text := utf16.Encode([]rune(text.Text))
-
-	for _, entity := range text.Entities {
-		if slices.Contains(blacklist, entity.Type.TextEntityTypeType()) {
-			if entity.Offset >= 0 &&
-				entity.Offset < int32(len(u16Text)) &&
-				entity.Offset+entity.Length <= int32(len(u16Text)) {
+	return eh.subs != nil && eh.subs.Has(chatId)
+}
 
-				u16Text = append(
-					u16Text[:entity.Offset],
-					u16Text[entity.Offset+entity.Length:]...)
-			}
+func (eh *EventHandler) handleUpdate(ctx context.Context, update client.Type) {
+	switch msg := update.(type) {
+	case *client.UpdateChatTitle:
+		eh.chats.Invalidate(msg.ChatId)
+	case *client.UpdateSupergroup:
+		eh.chats.InvalidateSupergroup(msg.Supergroup.Id)
+	case *client.UpdateNewMessage:
+		if eh.subs == nil || !eh.subs.Has(msg.Message.ChatId) {
+			return
+		}
+		err := eh.newMessageHandler(ctx, msg)
+		if err != nil {
+			zap.L().Error("Unable to handle new message", zap.Error(err))
+		}
+	// Single edit usually produces both updates, but
+	// since edits are emitted as upserts, duplicates are harmless
+	case *client.UpdateMessageContent:
+		if eh.subs == nil || !eh.subs.Has(msg.ChatId) {
+			return
+		}
+		err := eh.editedMessageHandler(ctx, msg.ChatId, msg.MessageId)
+		if err != nil {
+			zap.L().Error("Unable to handle message content", zap.Error(err))
+		}
+	case *client.UpdateMessageEdited:
+		if eh.subs == nil || !eh.subs.Has(msg.ChatId) {
+			return
+		}
+		err := eh.editedMessageHandler(ctx, msg.ChatId, msg.MessageId)
+		if err != nil {
+			zap.L().Error("Unable to handle edited message", zap.Error(err))
 		}
+	case *client.UpdateDeleteMessages:
+		if eh.subs == nil || !eh.subs.Has(msg.ChatId) {
+			return
+		}
+		eh.deleteMessagesHandler(msg)
 	}
-
-	return string(utf16.Decode(u16Text))
 }
 
-func (eh *EventHandler) newMessageHandler(msg *client.UpdateNewMessage) error {
+func (eh *EventHandler) newMessageHandler(
+	ctx context.Context,
+	msg *client.UpdateNewMessage,
+) error {
 	zap.L().Debug(
 		"New message",
 		zap.String("chat_id", fmt.Sprintf(
@@ -100,38 +180,93 @@ func (eh *EventHandler) newMessageHandler(msg *client.UpdateNewMessage) error {
 			msg.Message.Id,
 		)),
 	)
-	switch content := msg.Message.Content.(type) {
-	case *client.MessageText:
-		zap.L().Debug(
-			"New message text",
-			zap.String("text", content.Text.Text),
-		)
-		processedText := processText(content.Text)
-		zap.L().Debug("Processed text", zap.String("text", processedText))
-
-		chat, err := eh.client.GetChat(&client.GetChatRequest{
-			ChatId: msg.Message.ChatId,
+
+	return eh.forward(ctx, msg.Message, pb.ChangeType_CHANGE_TYPE_NEW)
+}
+
+func (eh *EventHandler) editedMessageHandler(
+	ctx context.Context,
+	chatId int64,
+	messageId int64,
+) error {
+	zap.L().Debug(
+		"Edited message",
+		zap.Int64("chat_id", chatId),
+		zap.Int64("message_id", messageId),
+	)
+
+	message, err := limiter.Call(ctx, func() (*client.Message, error) {
+		return eh.client.GetMessage(&client.GetMessageRequest{
+			ChatId:    chatId,
+			MessageId: messageId,
 		})
-		if err != nil {
-			zap.L().Error("Unable to get chat")
-			return err
-		}
+	})
+	if err != nil {
+		zap.L().Error("Unable to get message")
+		return err
+	}
 
-		username, err := ExtractUsername(eh.client, chat)
-		if err != nil {
-			zap.L().Error("Unable to extract username", zap.Error(err))
-			return err
-		}
+	return eh.forward(ctx, message, pb.ChangeType_CHANGE_TYPE_EDITED)
+}
 
-		if len([]rune(processedText)) > 50 {
-			eh.outputCh <- &pb.Message{
-				Text: processedText,
-				Ts:   timestamppb.New(time.Unix(int64(msg.Message.Date), 0)),
-				Link: fmt.Sprintf("https://t.me/%s/%d", username, msg.Message.Id),
-			}
-			zap.L().Debug("Processed text is sent to output channel")
+func (eh *EventHandler) deleteMessagesHandler(msg *client.UpdateDeleteMessages) {
+	if !msg.IsPermanent || msg.FromCache {
+		return
+	}
+
+	zap.L().Debug(
+		"Deleted messages",
+		zap.Int64("chat_id", msg.ChatId),
+		zap.Int64s("message_ids", msg.MessageIds),
+	)
+
+	now := timestamppb.Now()
+	for _, id := range msg.MessageIds {
+		eh.outputCh <- &pb.Message{
+			Ts:         now,
+			ChatId:     msg.ChatId,
+			MessageId:  id,
+			ChangeType: pb.ChangeType_CHANGE_TYPE_DELETED,
 		}
 	}
+}
+
+func (eh *EventHandler) forward(
+	ctx context.Context,
+	message *client.Message,
+	change pb.ChangeType,
+) error {
+	chat, err := eh.chats.Get(ctx, message.ChatId)
+	if err != nil {
+		zap.L().Error("Unable to resolve chat", zap.Error(err))
+		return err
+	}
+
+	out, ok := newMessage(message, chat, eh.text)
+	if !ok {
+		return nil
+	}
+	out.ChangeType = change
+	zap.L().Debug(
+		"Processed text",
+		zap.String("text", out.Text),
+		zap.String("media_type", out.MediaType.String()),
+	)
+
+	// Downloads would block the update loop, so media is
+	// uploaded in background and followed by an edit that references it
+	file, mimeType, hasMedia := mediaFile(message)
+	var pending *pb.Message
+	if eh.media != nil && hasMedia {
+		pending = proto.Clone(out).(*pb.Message)
+	}
+
+	eh.outputCh <- out
+	zap.L().Debug("Processed text is sent to output channel")
+
+	if pending != nil {
+		eh.media.Enqueue(file, mimeType, pending, eh.outputCh)
+	}
 
 	return nil
 }
@@ -1,15 +1,31 @@
 package tl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/nrydanov/inbrief/internal/chats"
+	"github.com/nrydanov/inbrief/internal/tl/limiter"
+
 	"github.com/zelenin/go-tdlib/client"
 	"go.uber.org/zap"
 )
 
 type ChatId int64
 
+func CheckChatFolder(
+	ctx context.Context,
+	c *client.Client,
+	link string,
+) (*client.ChatFolderInviteLinkInfo, error) {
+	return limiter.Call(ctx, func() (*client.ChatFolderInviteLinkInfo, error) {
+		return c.CheckChatFolderInviteLink(&client.CheckChatFolderInviteLinkRequest{
+			InviteLink: link,
+		})
+	})
+}
+
 func ExtractChatIds(info *client.ChatFolderInviteLinkInfo) []ChatId {
 	ids := make([]ChatId, len(info.AddedChatIds))
 
@@ -20,21 +36,156 @@ func ExtractChatIds(info *client.ChatFolderInviteLinkInfo) []ChatId {
 	return ids
 }
 
-func ExtractUsername(c *client.Client, chat *client.Chat) (string, error) {
+// JoinChatFolder adds the folder behind the invite link to the account,
+// joining the chats that aren't joined yet, and returns all chat ids from
+// the link.
+func JoinChatFolder(
+	ctx context.Context,
+	c *client.Client,
+	link string,
+	info *client.ChatFolderInviteLinkInfo,
+) ([]ChatId, error) {
+	ids := ExtractChatIds(info)
+
+	if len(info.MissingChatIds) == 0 {
+		return ids, nil
+	}
+
+	_, err := limiter.Call(ctx, func() (*client.Ok, error) {
+		return c.AddChatFolderByInviteLink(&client.AddChatFolderByInviteLinkRequest{
+			InviteLink: link,
+			ChatIds:    info.MissingChatIds,
+		})
+	})
+	if err != nil {
+		zap.L().Debug("Unable to add chat folder", zap.Error(err))
+		return nil, err
+	}
+
+	for _, id := range info.MissingChatIds {
+		ids = append(ids, ChatId(id))
+	}
+
+	return ids, nil
+}
+
+// ResolveUsernames returns usernames of the given chats, skipping the ones
+// that can't be resolved.
+func ResolveUsernames(ctx context.Context, cache *chats.Cache, ids []ChatId) []string {
+	usernames := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		info, err := cache.Get(ctx, int64(id))
+		if err != nil {
+			zap.L().Debug("Unable to resolve chat", zap.Error(err))
+			continue
+		}
+		if info.Username == "" {
+			continue
+		}
+
+		usernames = append(usernames, info.Username)
+	}
+
+	return usernames
+}
+
+// ResolveChat returns function that loads chat metadata from TDLib, to be
+// used by chat cache.
+func ResolveChat(c *client.Client) chats.ResolveFunc {
+	return func(ctx context.Context, chatId int64) (*chats.Info, error) {
+		chat, err := limiter.Call(ctx, func() (*client.Chat, error) {
+			return c.GetChat(&client.GetChatRequest{
+				ChatId: chatId,
+			})
+		})
+		if err != nil {
+			zap.L().Debug("Unable to get chat", zap.Int64("id", chatId))
+			return nil, err
+		}
+
+		username, err := ExtractUsername(ctx, c, chat)
+		if err != nil {
+			zap.L().Debug("Unable to extract username", zap.Error(err))
+			return nil, err
+		}
+
+		return &chats.Info{
+			Id:       chat.Id,
+			Username: username,
+			Title:    chat.Title,
+			Type:     chat.Type,
+		}, nil
+	}
+}
+
+var ErrUnsupportedChat = errors.New("unsupported chat type")
+
+// ChatError is returned when chat metadata can't be resolved.
+type ChatError struct {
+	ChatId int64
+	Type   string
+	Err    error
+}
+
+func (e *ChatError) Error() string {
+	return fmt.Sprintf("chat %d (%s): %v", e.ChatId, e.Type, e.Err)
+}
+
+func (e *ChatError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractUsername returns the primary username of the chat. Basic groups and
+// private supergroups have no username, so empty string is returned for them.
+func ExtractUsername(
+	ctx context.Context,
+	c *client.Client,
+	chat *client.Chat,
+) (string, error) {
 	switch e := chat.Type.(type) {
 	case *client.ChatTypeSupergroup:
-		group, err := c.GetSupergroup(&client.GetSupergroupRequest{
-			SupergroupId: e.SupergroupId,
+		group, err := limiter.Call(ctx, func() (*client.Supergroup, error) {
+			return c.GetSupergroup(&client.GetSupergroupRequest{
+				SupergroupId: e.SupergroupId,
+			})
 		})
 		if err != nil {
 			zap.L().Debug("Unable to convert chat to supergroup", zap.Error(err))
-			return "", err
+			return "", &ChatError{
+				ChatId: chat.Id,
+				Type:   chat.Type.ChatTypeType(),
+				Err:    err,
+			}
 		}
-		if group.Usernames == nil {
-			return fmt.Sprintf("%d", chat.Id), nil
+		if group.Usernames == nil || len(group.Usernames.ActiveUsernames) == 0 {
+			return "", nil
 		}
 		return group.Usernames.ActiveUsernames[0], nil
+	case *client.ChatTypeBasicGroup:
+		return "", nil
 	default:
-		return "", errors.New("unsupported chat type")
+		return "", &ChatError{
+			ChatId: chat.Id,
+			Type:   chat.Type.ChatTypeType(),
+			Err:    ErrUnsupportedChat,
+		}
+	}
+}
+
+// MessageLink builds public t.me link for public chats and t.me/c link for
+// private supergroups. Basic groups have no message links.
+func MessageLink(chat *chats.Info, messageId int64) string {
+	// TDLib message ids are server ids shifted by 20 bits
+	serverId := messageId >> 20
+
+	if chat.Username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.Username, serverId)
 	}
+
+	if group, ok := chat.Type.(*client.ChatTypeSupergroup); ok {
+		return fmt.Sprintf("https://t.me/c/%d/%d", group.SupergroupId, serverId)
+	}
+
+	return ""
 }
@@ -0,0 +1,52 @@
+package tl
+
+import (
+	"testing"
+
+	"github.com/nrydanov/inbrief/internal/chats"
+
+	"github.com/zelenin/go-tdlib/client"
+)
+
+func TestMessageLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		chat      *chats.Info
+		messageId int64
+		want      string
+	}{
+		{
+			name: "public channel",
+			chat: &chats.Info{
+				Username: "inbrief",
+				Type:     &client.ChatTypeSupergroup{SupergroupId: 1234, IsChannel: true},
+			},
+			messageId: 42 << 20,
+			want:      "https://t.me/inbrief/42",
+		},
+		{
+			name: "private supergroup",
+			chat: &chats.Info{
+				Type: &client.ChatTypeSupergroup{SupergroupId: 1234},
+			},
+			messageId: 7 << 20,
+			want:      "https://t.me/c/1234/7",
+		},
+		{
+			name: "basic group",
+			chat: &chats.Info{
+				Type: &client.ChatTypeBasicGroup{BasicGroupId: 1234},
+			},
+			messageId: 7 << 20,
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessageLink(tt.chat, tt.messageId); got != tt.want {
+				t.Errorf("MessageLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,39 +1,49 @@
 package internal
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/s3"
 	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
-	"github.com/redis/go-redis/v9"
+	"github.com/nrydanov/inbrief/internal/dedup"
+	"github.com/nrydanov/inbrief/internal/sink"
+	"github.com/nrydanov/inbrief/internal/subscription"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const notificationVersion = 1
+
 type Writer struct {
-	inputCh   <-chan *pb.Message
-	s3Client  *s3.S3
-	rdb       *redis.Client
-	publishCh string
+	inputCh  <-chan *pb.Message
+	source   string
+	sink     sink.Sink
+	notifier Notifier
+	cursors  *subscription.Cursors
+	seen     *dedup.Seen
+	nearDup  *dedup.NearDuplicates
 }
 
 func NewWriter(
 	ch <-chan *pb.Message,
-	s3 *s3.S3,
-	rdb *redis.Client,
-	publishCh string,
+	source string,
+	sink sink.Sink,
+	notifier Notifier,
+	cursors *subscription.Cursors,
+	seen *dedup.Seen,
+	nearDup *dedup.NearDuplicates,
 ) *Writer {
 	return &Writer{
-		inputCh:   ch,
-		s3Client:  s3,
-		rdb:       rdb,
-		publishCh: publishCh,
+		inputCh:  ch,
+		source:   source,
+		sink:     sink,
+		notifier: notifier,
+		cursors:  cursors,
+		seen:     seen,
+		nearDup:  nearDup,
 	}
 }
 
@@ -92,56 +102,117 @@ func (n *Writer) Listen(ctx context.Context, bufferSize int) {
 }
 
 func (n *Writer) flush(msgs []*pb.Message) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
+	// Writer fails open, i.e. messages are written even if
+	// seen set is unavailable
+	var claimed []*pb.Message
+	if n.seen != nil {
+		filtered, err := n.seen.Filter(ctx, msgs)
+		if err != nil {
+			zap.L().Error("Failed to deduplicate messages", zap.Error(err))
+		} else {
+			if dropped := len(msgs) - len(filtered); dropped > 0 {
+				zap.L().Info("Dropped duplicate messages", zap.Int("count", dropped))
+			}
+			msgs = filtered
+			claimed = filtered
+		}
+	}
+
+	if n.nearDup != nil {
+		msgs = n.nearDup.Process(msgs)
+	}
+
 	nMsgs := len(msgs)
+	if n.sink == nil {
+		zap.L().Debug("No sinks configured, dropping messages", zap.Int("count", nMsgs))
+		return nil
+	}
 	if nMsgs == 0 {
 		zap.L().Info("Nothing to flush since last time")
 		return nil
 	}
 	zap.L().Info(fmt.Sprintf("Flushing %d messages since last time", nMsgs))
 
-	id := time.Now().UnixNano()
+	now := time.Now()
+	id := now.UnixNano()
 
-	marshaler := protojson.MarshalOptions{
-		UseEnumNumbers:  false,
-		EmitUnpopulated: true,
-		Indent:          "  ",
+	batch := sink.Batch{
+		Id:        fmt.Sprintf("%d", id),
+		Source:    n.source,
+		CreatedAt: now,
+		Messages:  msgs,
+	}
+	objects, err := n.sink.Write(ctx, batch)
+	if err != nil {
+		if n.seen != nil {
+			if err := n.seen.Release(ctx, claimed); err != nil {
+				zap.L().Error("Failed to release seen messages", zap.Error(err))
+			}
+		}
+		return err
 	}
 
-	jsonMessages := make([]json.RawMessage, nMsgs)
-	for i, msg := range msgs {
-		jsonData, err := marshaler.Marshal(msg)
-		if err != nil {
-			zap.L().Error("Failed to marshal proto message", zap.Error(err))
-			return err
+	zap.L().Info(
+		"Successfully flushed messages",
+		zap.Int("count", nMsgs),
+		zap.String("id", fmt.Sprintf("%d", id)),
+	)
+
+	if n.cursors != nil {
+		last := make(map[int64]int64)
+		for _, msg := range msgs {
+			last[msg.ChatId] = max(last[msg.ChatId], msg.MessageId)
+		}
+		if err := n.cursors.Advance(ctx, last); err != nil {
+			zap.L().Error("Failed to advance cursors", zap.Error(err))
 		}
-		jsonMessages[i] = json.RawMessage(jsonData)
 	}
 
-	marshalled, err := json.Marshal(jsonMessages)
-	if err != nil {
-		zap.L().Error("Failed to marshal messages", zap.Error(err))
-		return err
+	if n.notifier == nil || len(objects) == 0 {
+		return nil
 	}
 
-	_, err = n.s3Client.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String("inbrief"),
-		Key:    aws.String(fmt.Sprintf("%d.json", id)),
-		Body:   bytes.NewReader(marshalled),
-	})
+	// Consumers are notified about object of the first sink
+	payload, err := protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}.Marshal(newNotification(batch, objects[0]))
 	if err != nil {
-		zap.L().Error("Failed to upload messages to S3", zap.Error(err))
 		return err
 	}
 
-	zap.L().Info(
-		"Successfully flushed messages",
-		zap.Int("count", nMsgs),
-		zap.String("id", fmt.Sprintf("%d", id)),
-	)
+	return n.notifier.Notify(ctx, payload)
+
+}
+
+func newNotification(batch sink.Batch, object sink.Object) *pb.BatchNotification {
+	notification := &pb.BatchNotification{
+		Version:      notificationVersion,
+		BatchId:      batch.Id,
+		Sink:         object.Sink,
+		Bucket:       object.Bucket,
+		Key:          object.Key,
+		Format:       object.Format,
+		MessageCount: int32(len(batch.Messages)),
+		Source:       batch.Source,
+		Checksum:     object.Checksum,
+		CreatedAt:    timestamppb.New(batch.CreatedAt),
+	}
 
-	return n.rdb.Publish(ctx, n.publishCh, id).Err()
+	for _, msg := range batch.Messages {
+		if msg.Ts == nil {
+			continue
+		}
+		if notification.MinTs == nil || msg.Ts.AsTime().Before(notification.MinTs.AsTime()) {
+			notification.MinTs = msg.Ts
+		}
+		if notification.MaxTs == nil || msg.Ts.AsTime().After(notification.MaxTs.AsTime()) {
+			notification.MaxTs = msg.Ts
+		}
+	}
 
+	return notification
 }
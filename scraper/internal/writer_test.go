@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nrydanov/inbrief/gen/proto/fetcher"
+	"github.com/nrydanov/inbrief/internal/sink"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNewNotification(t *testing.T) {
+	createdAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	ts := func(minutes int) *timestamppb.Timestamp {
+		return timestamppb.New(createdAt.Add(-time.Duration(minutes) * time.Minute))
+	}
+	object := sink.Object{
+		Sink:     sink.KindS3,
+		Bucket:   "inbrief",
+		Key:      "dt=2026-05-01/hour=12/source=stream/a1.jsonl",
+		Format:   sink.FormatJSONL,
+		Checksum: "sha256:0123",
+	}
+
+	tests := []struct {
+		name     string
+		messages []*pb.Message
+		count    int32
+		minTs    *timestamppb.Timestamp
+		maxTs    *timestamppb.Timestamp
+	}{
+		{
+			name:     "unordered",
+			messages: []*pb.Message{{Ts: ts(5)}, {Ts: ts(30)}, {Ts: ts(1)}},
+			count:    3,
+			minTs:    ts(30),
+			maxTs:    ts(1),
+		},
+		{
+			name:     "some without ts",
+			messages: []*pb.Message{{}, {Ts: ts(10)}, {}, {Ts: ts(20)}},
+			count:    4,
+			minTs:    ts(20),
+			maxTs:    ts(10),
+		},
+		{
+			name:     "none with ts",
+			messages: []*pb.Message{{}, {}},
+			count:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := sink.Batch{
+				Id:        "a1",
+				Source:    sink.SourceStream,
+				CreatedAt: createdAt,
+				Messages:  tt.messages,
+			}
+
+			n := newNotification(batch, object)
+			if n.Version != notificationVersion || n.BatchId != batch.Id || n.Source != batch.Source {
+				t.Errorf("notification = %v, want batch %s", n, batch.Id)
+			}
+			if n.Sink != object.Sink || n.Bucket != object.Bucket || n.Key != object.Key || n.Format != object.Format {
+				t.Errorf("notification = %v, want object %+v", n, object)
+			}
+			if n.Checksum != object.Checksum {
+				t.Errorf("checksum = %s, want %s", n.Checksum, object.Checksum)
+			}
+			if n.MessageCount != tt.count {
+				t.Errorf("message count = %d, want %d", n.MessageCount, tt.count)
+			}
+			if !n.CreatedAt.AsTime().Equal(createdAt) {
+				t.Errorf("created at = %s, want %s", n.CreatedAt.AsTime(), createdAt)
+			}
+			if !equalTs(n.MinTs, tt.minTs) || !equalTs(n.MaxTs, tt.maxTs) {
+				t.Errorf("ts range = [%v, %v], want [%v, %v]", n.MinTs, n.MaxTs, tt.minTs, tt.maxTs)
+			}
+		})
+	}
+}
+
+func equalTs(a, b *timestamppb.Timestamp) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.AsTime().Equal(b.AsTime())
+}
@@ -0,0 +1,37 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEach calls fn for every item using at most workers goroutines and waits
+// for all of them to finish. Items that haven't been started before ctx is
+// cancelled are skipped.
+func ForEach[T any](
+	ctx context.Context,
+	items []T,
+	workers int,
+	fn func(i int, item T),
+) {
+	sem := make(chan struct{}, max(workers, 1))
+	wg := sync.WaitGroup{}
+
+	for i, item := range items {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, item)
+		}()
+	}
+
+	wg.Wait()
+}
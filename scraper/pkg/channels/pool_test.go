@@ -0,0 +1,68 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	results := make([]int, len(items))
+
+	var running, peak atomic.Int32
+	ForEach(context.Background(), items, 3, func(i int, item int) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		results[i] = item * item
+	})
+
+	for i, item := range items {
+		if results[i] != item*item {
+			t.Errorf("results[%d] = %d, want %d", i, results[i], item*item)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d items processed concurrently, want at most 3", p)
+	}
+}
+
+func TestForEachNonPositiveWorkers(t *testing.T) {
+	count := 0
+	ForEach(context.Background(), []string{"a", "b"}, 0, func(int, string) {
+		count++
+	})
+	if count != 2 {
+		t.Errorf("processed %d items, want 2", count)
+	}
+}
+
+func TestForEachCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mu := sync.Mutex{}
+	started := 0
+	ForEach(ctx, make([]struct{}, 10), 1, func(int, struct{}) {
+		mu.Lock()
+		started++
+		mu.Unlock()
+		cancel()
+		// Worker is kept busy, so that only cancellation is
+		// ready when the next item is scheduled
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	if started != 1 {
+		t.Errorf("started %d items, want items after cancellation to be skipped", started)
+	}
+}